@@ -5,10 +5,37 @@ import (
 	"os"
 	"os/user"
 
+	"punyGo/pkg/evaluator"
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/object"
+	"punyGo/pkg/parser"
 	"punyGo/pkg/repl"
+	"punyGo/pkg/types"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "--infer" { // infer and print the types of a source file
+		if len(os.Args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: punyGo --infer <file>")
+			os.Exit(2)
+		}
+		os.Exit(inferFile(os.Args[2]))
+	}
+
+	if len(os.Args) > 1 { // run a source file as the main module
+		status := 0
+		result := evaluator.RunFile(os.Args[1])
+		if errObj, ok := result.(*object.Error); ok {
+			fmt.Fprintln(os.Stderr, errObj.Inspect())
+			status = 1
+		}
+		for _, errObj := range evaluator.UnobservedTaskErrors() { // failures of tasks nobody waited for
+			fmt.Fprintln(os.Stderr, "unobserved task error: "+errObj.Inspect())
+			status = 1
+		}
+		os.Exit(status)
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -18,3 +45,42 @@ func main() {
 	fmt.Printf("Feel free to type in commands\n")
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+// inferFile infers the types of a source file without running it, prints the
+// signatures of its top-level declarations and returns the exit status
+func inferFile(path string) int {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	par := parser.New(lexer.New(string(source)))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		for _, msg := range par.Errors() {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, msg)
+		}
+		return 1
+	}
+
+	macroEnv := object.NewEnvironment(nil)
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, errObj := evaluator.ExpandMacros(program, macroEnv)
+	if errObj != nil {
+		fmt.Fprintln(os.Stderr, errObj.Inspect())
+		return 1
+	}
+
+	signatures, errs := types.Infer(expanded)
+	for _, sig := range signatures {
+		fmt.Println(sig)
+	}
+	for _, msg := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, msg)
+	}
+	if len(errs) != 0 {
+		return 1
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"strings"
 
 	"punyGo/pkg/token"
 )
@@ -54,9 +55,11 @@ func (p *Program) String() string {
 
 // LetStatement 代表 let 语句节点
 type LetStatement struct {
-	Token token.Token // token.LET 词法单元
-	Name  *Identifier // 变量名
-	Value Expression  // 变量值表达式
+	Token token.Token    // token.LET 词法单元
+	Name  *Identifier    // 变量名，解构赋值时为 nil
+	Names []*Identifier  // 解构赋值的变量名，例如 let (q, r) = divmod(a, b);
+	Type  TypeExpression // 可选的类型标注，例如 let x: int = 5; 没有时为 nil
+	Value Expression     // 变量值表达式
 }
 
 // statementNode 实现 Statement 接口，用于标识 LetStatement 是一个语句节点
@@ -70,8 +73,19 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ") // 1. 写入 "let "
-	out.WriteString(ls.Name.String())        // 2. 写入变量名
-	out.WriteString(" = ")                   // 3. 写入 " = "
+	if ls.Name != nil {
+		out.WriteString(ls.Name.String()) // 2. 写入变量名
+	} else {
+		names := []string{}
+		for _, n := range ls.Names {
+			names = append(names, n.String())
+		}
+		out.WriteString("(" + strings.Join(names, ", ") + ")") // 2.1. 写入解构的变量名
+	}
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String()) // 2.2. 写入类型标注
+	}
+	out.WriteString(" = ") // 3. 写入 " = "
 
 	if ls.Value != nil {
 		out.WriteString(ls.Value.String()) // 4. 写入变量值的字符串表示
@@ -157,6 +171,31 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+// PostfixExpression 代表后缀表达式节点，例如 result?
+type PostfixExpression struct {
+	Token    token.Token // 操作符的词法单元，如 '?'
+	Left     Expression  // 操作符左侧的表达式
+	Operator string      // 操作符的字符串表示
+}
+
+// expressionNode 实现 Expression 接口，用于标识 PostfixExpression 是一个表达式节点
+func (pe *PostfixExpression) expressionNode() {}
+
+// TokenLiteral 返回后缀表达式的操作符词法字面量
+func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+
+// String 返回后缀表达式的字符串表示，例如 "(result?)"
+func (pe *PostfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")              // 1. 写入左括号
+	out.WriteString(pe.Left.String()) // 2. 写入左侧表达式的字符串表示
+	out.WriteString(pe.Operator)      // 3. 写入操作符
+	out.WriteString(")")              // 4. 写入右括号
+
+	return out.String()
+}
+
 // InfixExpression 代表中缀表达式节点，例如 5 + 5
 type InfixExpression struct {
 	Token    token.Token // 操作符的词法单元，如 '+'
@@ -183,3 +222,1125 @@ func (ie *InfixExpression) String() string {
 
 	return out.String()
 }
+
+// Boolean 代表布尔字面量节点，即 true 或 false
+type Boolean struct {
+	Token token.Token // token.TRUE 或 token.FALSE 词法单元
+	Value bool        // 布尔值
+}
+
+// expressionNode 实现 Expression 接口，用于标识 Boolean 是一个表达式节点
+func (b *Boolean) expressionNode() {}
+
+// TokenLiteral 返回布尔字面量的词法字面量
+func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+
+// String 返回布尔字面量的字符串表示
+func (b *Boolean) String() string { return b.Token.Literal }
+
+// NilLiteral 代表空值字面量节点，即 nil
+type NilLiteral struct {
+	Token token.Token // token.NIL 词法单元
+}
+
+// expressionNode 实现 Expression 接口，用于标识 NilLiteral 是一个表达式节点
+func (n *NilLiteral) expressionNode() {}
+
+// TokenLiteral 返回空值字面量的词法字面量
+func (n *NilLiteral) TokenLiteral() string { return n.Token.Literal }
+
+// String 返回空值字面量的字符串表示
+func (n *NilLiteral) String() string { return n.Token.Literal }
+
+// CallExpression 代表调用表达式节点，例如 add(1, 2)
+type CallExpression struct {
+	Token     token.Token  // '(' 词法单元
+	Function  Expression   // 被调用的表达式
+	Arguments []Expression // 实参列表
+}
+
+// expressionNode 实现 Expression 接口，用于标识 CallExpression 是一个表达式节点
+func (ce *CallExpression) expressionNode() {}
+
+// TokenLiteral 返回调用表达式的词法字面量
+func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+
+// String 返回调用表达式的字符串表示，例如 "add(1, 2)"
+func (ce *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+	for _, a := range ce.Arguments {
+		args = append(args, a.String()) // 1. 收集每个实参的字符串表示
+	}
+
+	out.WriteString(ce.Function.String())     // 2. 写入被调用的表达式
+	out.WriteString("(")                      // 3. 写入左括号
+	out.WriteString(strings.Join(args, ", ")) // 4. 写入以逗号分隔的实参
+	out.WriteString(")")                      // 5. 写入右括号
+
+	return out.String()
+}
+
+// EnumVariant 代表枚举中的一个变体，例如 Rect(w, h) 或 Some(value: T)
+type EnumVariant struct {
+	Name       *Identifier      // 变体名称
+	Fields     []*Identifier    // 变体携带的字段名，无字段时为空
+	FieldTypes []TypeExpression // 与字段一一对应的类型标注，没有标注的字段对应 nil
+}
+
+// String 返回变体的字符串表示，例如 "Rect(w, h)" 或 "Empty"
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.String() // 1. 无字段的变体只输出名称
+	}
+
+	fields := []string{}
+	for i, f := range ev.Fields {
+		field := f.String()
+		if i < len(ev.FieldTypes) && ev.FieldTypes[i] != nil {
+			field += ": " + ev.FieldTypes[i].String()
+		}
+		fields = append(fields, field) // 2. 收集字段名和类型标注
+	}
+
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")" // 3. 输出名称和字段列表
+}
+
+// EnumStatement 代表枚举声明语句节点，例如 enum Shape { Circle(r), Rect(w, h), Empty } 或 enum Option<T> { Some(value: T), None }
+type EnumStatement struct {
+	Token      token.Token    // token.ENUM 词法单元
+	Name       *Identifier    // 枚举名称
+	TypeParams []*Identifier  // 类型参数，不是泛型枚举时为空
+	Variants   []*EnumVariant // 变体列表
+}
+
+// statementNode 实现 Statement 接口，用于标识 EnumStatement 是一个语句节点
+func (es *EnumStatement) statementNode() {}
+
+// TokenLiteral 返回枚举声明的词法字面量
+func (es *EnumStatement) TokenLiteral() string { return es.Token.Literal }
+
+// String 返回枚举声明的字符串表示
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String()) // 1. 收集每个变体的字符串表示
+	}
+
+	out.WriteString(es.TokenLiteral() + " ")         // 2. 写入 "enum "
+	out.WriteString(es.Name.String())                // 3. 写入枚举名称
+	out.WriteString(typeParamsString(es.TypeParams)) // 3.1. 写入类型参数
+	out.WriteString(" { ")                           // 4. 写入左大括号
+	out.WriteString(strings.Join(variants, ", "))    // 5. 写入以逗号分隔的变体
+	out.WriteString(" }")                            // 6. 写入右大括号
+
+	return out.String()
+}
+
+// ImplStatement 代表为枚举定义方法的语句节点，例如 impl Vec { fn __add__(self, other) { ... } }
+type ImplStatement struct {
+	Token   token.Token        // token.IMPL 词法单元
+	Name    *Identifier        // 枚举名称
+	Methods []*FunctionLiteral // 方法列表，Name 均不为空，第一个形参接收调用方法的枚举值
+}
+
+// statementNode 实现 Statement 接口，用于标识 ImplStatement 是一个语句节点
+func (is *ImplStatement) statementNode() {}
+
+// TokenLiteral 返回 impl 语句的词法字面量
+func (is *ImplStatement) TokenLiteral() string { return is.Token.Literal }
+
+// String 返回 impl 语句的字符串表示
+func (is *ImplStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(is.TokenLiteral() + " ") // 1. 写入 "impl "
+	out.WriteString(is.Name.String())        // 2. 写入枚举名称
+	out.WriteString(" { ")                   // 3. 写入左大括号
+	for _, m := range is.Methods {
+		out.WriteString(m.String() + " ") // 4. 依次写入每个方法
+	}
+	out.WriteString("}") // 5. 写入右大括号
+
+	return out.String()
+}
+
+// BlockStatement 代表由大括号包围的语句块节点
+type BlockStatement struct {
+	Token      token.Token // '{' 词法单元
+	Statements []Statement // 语句块中的语句列表
+}
+
+// statementNode 实现 Statement 接口，用于标识 BlockStatement 是一个语句节点
+func (bs *BlockStatement) statementNode() {}
+
+// TokenLiteral 返回语句块的词法字面量
+func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+
+// String 返回语句块的字符串表示，例如 "{ let x = 1; x }"
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	stmts := []string{}
+	for _, s := range bs.Statements {
+		stmts = append(stmts, s.String()) // 1. 收集每条语句的字符串表示
+	}
+
+	out.WriteString("{ ")                     // 2. 写入左大括号
+	out.WriteString(strings.Join(stmts, " ")) // 3. 写入以空格分隔的语句
+	out.WriteString(" }")                     // 4. 写入右大括号
+
+	return out.String()
+}
+
+// ThrowStatement 代表 throw 语句节点，例如 throw x;
+type ThrowStatement struct {
+	Token token.Token // token.THROW 词法单元
+	Value Expression  // 被抛出的表达式
+}
+
+// statementNode 实现 Statement 接口，用于标识 ThrowStatement 是一个语句节点
+func (ts *ThrowStatement) statementNode() {}
+
+// TokenLiteral 返回 throw 语句的词法字面量
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+
+// String 返回 throw 语句的字符串表示，例如 "throw x;"
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+// DeferStatement 代表 defer 语句节点，例如 defer close(ch);
+type DeferStatement struct {
+	Token token.Token     // token.DEFER 词法单元
+	Call  *CallExpression // 推迟执行的调用
+}
+
+// statementNode 实现 Statement 接口，用于标识 DeferStatement 是一个语句节点
+func (ds *DeferStatement) statementNode() {}
+
+// TokenLiteral 返回 defer 语句的词法字面量
+func (ds *DeferStatement) TokenLiteral() string { return ds.Token.Literal }
+
+// String 返回 defer 语句的字符串表示，例如 "defer close(ch);"
+func (ds *DeferStatement) String() string {
+	return ds.TokenLiteral() + " " + ds.Call.String() + ";"
+}
+
+// TryStatement 代表 try/catch/finally 语句节点，catch 和 finally 至少存在一个
+type TryStatement struct {
+	Token      token.Token     // token.TRY 词法单元
+	Block      *BlockStatement // try 语句块
+	CatchParam *Identifier     // catch 绑定错误值的参数名，可为空
+	Catch      *BlockStatement // catch 语句块，可为空
+	Finally    *BlockStatement // finally 语句块，可为空
+}
+
+// statementNode 实现 Statement 接口，用于标识 TryStatement 是一个语句节点
+func (ts *TryStatement) statementNode() {}
+
+// TokenLiteral 返回 try 语句的词法字面量
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+
+// String 返回 try 语句的字符串表示，例如 "try { x } catch (e) { e } finally { y }"
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ") // 1. 写入 "try "
+	out.WriteString(ts.Block.String())       // 2. 写入 try 语句块
+
+	if ts.Catch != nil { // 3. 写入 catch 部分
+		out.WriteString(" catch ")
+		if ts.CatchParam != nil {
+			out.WriteString("(" + ts.CatchParam.String() + ") ")
+		}
+		out.WriteString(ts.Catch.String())
+	}
+
+	if ts.Finally != nil { // 4. 写入 finally 部分
+		out.WriteString(" finally ")
+		out.WriteString(ts.Finally.String())
+	}
+
+	return out.String()
+}
+
+// FunctionLiteral 代表函数字面量节点，例如 fn(x, y) { x + y } 或生成器 fn* count(n) { yield n; }
+type FunctionLiteral struct {
+	Token       token.Token      // token.FUNCTION 词法单元
+	Name        string           // 函数名，匿名函数为空
+	TypeParams  []*Identifier    // 类型参数，例如 fn first<T>(xs: [T]) -> T 中的 T，不是泛型函数时为空
+	IsGenerator bool             // 是否为 fn* 声明的生成器函数
+	Parameters  []*Identifier    // 形参列表
+	Defaults    []Expression     // 与形参一一对应的默认值，没有默认值的形参对应 nil
+	Rest        *Identifier      // 以 "..." 声明的可变参数，收集多余的实参，没有时为 nil
+	ParamTypes  []TypeExpression // 与形参一一对应的类型标注，没有标注的形参对应 nil
+	RestType    TypeExpression   // 可变参数的类型标注，即收集实参的数组的类型，没有时为 nil
+	ReturnType  TypeExpression   // 返回值的类型标注，没有时为 nil
+	Body        *BlockStatement  // 函数体
+}
+
+// expressionNode 实现 Expression 接口，用于标识 FunctionLiteral 是一个表达式节点
+func (fl *FunctionLiteral) expressionNode() {}
+
+// TokenLiteral 返回函数字面量的词法字面量
+func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+
+// String 返回函数字面量的字符串表示，例如 "fn add(x, y) { (x + y) }"
+func (fl *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for i, p := range fl.Parameters {
+		param := p.String()
+		if i < len(fl.ParamTypes) && fl.ParamTypes[i] != nil {
+			param += ": " + fl.ParamTypes[i].String() // 1. 收集形参名、类型标注和默认值
+		}
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			param += " = " + fl.Defaults[i].String()
+		}
+		params = append(params, param)
+	}
+	if fl.Rest != nil {
+		rest := "..." + fl.Rest.String() // 1.1. 可变参数总是最后一个
+		if fl.RestType != nil {
+			rest += ": " + fl.RestType.String()
+		}
+		params = append(params, rest)
+	}
+
+	out.WriteString(fl.TokenLiteral()) // 2. 写入 "fn"
+	if fl.IsGenerator {
+		out.WriteString("*") // 3. 生成器函数写入 "*"
+	}
+	if fl.Name != "" {
+		out.WriteString(" " + fl.Name) // 4. 写入函数名
+	}
+	out.WriteString(typeParamsString(fl.TypeParams)) // 4.1. 写入类型参数
+	out.WriteString("(")                             // 5. 写入左括号
+	out.WriteString(strings.Join(params, ", "))      // 6. 写入以逗号分隔的形参
+	out.WriteString(") ")                            // 7. 写入右括号
+	if fl.ReturnType != nil {
+		out.WriteString("-> " + fl.ReturnType.String() + " ") // 7.1. 写入返回值的类型标注
+	}
+	out.WriteString(fl.Body.String()) // 8. 写入函数体
+
+	return out.String()
+}
+
+// FunctionStatement 代表具名函数声明语句节点，例如 fn add(x, y) { x + y }
+type FunctionStatement struct {
+	Token    token.Token      // token.FUNCTION 词法单元
+	Function *FunctionLiteral // 声明的函数，Name 不为空
+}
+
+// statementNode 实现 Statement 接口，用于标识 FunctionStatement 是一个语句节点
+func (fs *FunctionStatement) statementNode() {}
+
+// TokenLiteral 返回函数声明的词法字面量
+func (fs *FunctionStatement) TokenLiteral() string { return fs.Token.Literal }
+
+// String 返回函数声明的字符串表示
+func (fs *FunctionStatement) String() string { return fs.Function.String() }
+
+// ReturnStatement 代表 return 语句节点，例如 return x;
+type ReturnStatement struct {
+	Token       token.Token // token.RETURN 词法单元
+	ReturnValue Expression  // 返回值表达式
+}
+
+// statementNode 实现 Statement 接口，用于标识 ReturnStatement 是一个语句节点
+func (rs *ReturnStatement) statementNode() {}
+
+// TokenLiteral 返回 return 语句的词法字面量
+func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+
+// String 返回 return 语句的字符串表示，例如 "return x;"
+func (rs *ReturnStatement) String() string {
+	return rs.TokenLiteral() + " " + rs.ReturnValue.String() + ";"
+}
+
+// YieldStatement 代表 yield 语句节点，只能出现在生成器函数中，例如 yield i;
+type YieldStatement struct {
+	Token token.Token // token.YIELD 词法单元
+	Value Expression  // 产出的值
+}
+
+// statementNode 实现 Statement 接口，用于标识 YieldStatement 是一个语句节点
+func (ys *YieldStatement) statementNode() {}
+
+// TokenLiteral 返回 yield 语句的词法字面量
+func (ys *YieldStatement) TokenLiteral() string { return ys.Token.Literal }
+
+// String 返回 yield 语句的字符串表示，例如 "yield i;"
+func (ys *YieldStatement) String() string {
+	return ys.TokenLiteral() + " " + ys.Value.String() + ";"
+}
+
+// SpawnExpression 代表 spawn 表达式节点，在新的任务中执行一次调用，例如 spawn worker(ch)
+type SpawnExpression struct {
+	Token token.Token     // token.SPAWN 词法单元
+	Call  *CallExpression // 在新任务中执行的调用
+}
+
+// expressionNode 实现 Expression 接口，用于标识 SpawnExpression 是一个表达式节点
+func (se *SpawnExpression) expressionNode() {}
+
+// TokenLiteral 返回 spawn 表达式的词法字面量
+func (se *SpawnExpression) TokenLiteral() string { return se.Token.Literal }
+
+// String 返回 spawn 表达式的字符串表示，例如 "spawn worker(ch)"
+func (se *SpawnExpression) String() string {
+	return se.TokenLiteral() + " " + se.Call.String()
+}
+
+// SelectCase 代表 select 语句中的一个分支，例如 case v = recv(ch) { ... } 或 case send(ch, 1) { ... }
+type SelectCase struct {
+	Token token.Token     // token.CASE 词法单元
+	Name  *Identifier     // 绑定接收值的变量名，可为空
+	Comm  *CallExpression // 通信操作，recv(ch) 或 send(ch, value)
+	Body  *BlockStatement // 分支被选中时执行的语句块
+}
+
+// String 返回分支的字符串表示
+func (sc *SelectCase) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(sc.Token.Literal + " ") // 1. 写入 "case "
+	if sc.Name != nil {
+		out.WriteString(sc.Name.String() + " = ") // 2. 写入绑定的变量名
+	}
+	out.WriteString(sc.Comm.String() + " ") // 3. 写入通信操作
+	out.WriteString(sc.Body.String())       // 4. 写入语句块
+
+	return out.String()
+}
+
+// SelectStatement 代表 select 语句节点，等待多个通信操作中的一个就绪
+type SelectStatement struct {
+	Token   token.Token     // token.SELECT 词法单元
+	Cases   []*SelectCase   // 通信分支
+	Default *BlockStatement // 没有分支就绪时执行的语句块，可为空
+}
+
+// statementNode 实现 Statement 接口，用于标识 SelectStatement 是一个语句节点
+func (ss *SelectStatement) statementNode() {}
+
+// TokenLiteral 返回 select 语句的词法字面量
+func (ss *SelectStatement) TokenLiteral() string { return ss.Token.Literal }
+
+// String 返回 select 语句的字符串表示
+func (ss *SelectStatement) String() string {
+	var out bytes.Buffer
+
+	cases := []string{}
+	for _, c := range ss.Cases {
+		cases = append(cases, c.String()) // 1. 收集每个分支的字符串表示
+	}
+	if ss.Default != nil {
+		cases = append(cases, "default "+ss.Default.String()) // 2. 写入 default 分支
+	}
+
+	out.WriteString(ss.TokenLiteral() + " { ") // 3. 写入 "select { "
+	out.WriteString(strings.Join(cases, " "))  // 4. 写入所有分支
+	out.WriteString(" }")                      // 5. 写入右大括号
+
+	return out.String()
+}
+
+// StringLiteral 代表字符串字面量节点
+type StringLiteral struct {
+	Token token.Token // token.STRING 词法单元
+	Value string      // 字符串的内容
+}
+
+// expressionNode 实现 Expression 接口，用于标识 StringLiteral 是一个表达式节点
+func (sl *StringLiteral) expressionNode() {}
+
+// TokenLiteral 返回字符串字面量的词法字面量
+func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+
+// String 返回字符串字面量的字符串表示，带有双引号
+func (sl *StringLiteral) String() string { return "\"" + sl.Token.Literal + "\"" }
+
+// BytesLiteral 代表字节串字面量节点，例如 b"\x00\xff"
+type BytesLiteral struct {
+	Token token.Token // token.BYTES 词法单元，字面量为引号之间未经转义的内容
+	Value []byte      // 转义后的字节
+}
+
+// expressionNode 实现 Expression 接口，用于标识 BytesLiteral 是一个表达式节点
+func (bl *BytesLiteral) expressionNode() {}
+
+// TokenLiteral 返回字节串字面量的词法字面量
+func (bl *BytesLiteral) TokenLiteral() string { return bl.Token.Literal }
+
+// String 返回字节串字面量的字符串表示，例如 b"\x00\xff"
+func (bl *BytesLiteral) String() string { return "b\"" + bl.Token.Literal + "\"" }
+
+// CharLiteral 代表字符字面量节点，例如 'a'
+type CharLiteral struct {
+	Token token.Token // token.CHAR 词法单元，字面量为引号之间未经转义的内容
+	Value rune        // 字符
+}
+
+// expressionNode 实现 Expression 接口，用于标识 CharLiteral 是一个表达式节点
+func (cl *CharLiteral) expressionNode() {}
+
+// TokenLiteral 返回字符字面量的词法字面量
+func (cl *CharLiteral) TokenLiteral() string { return cl.Token.Literal }
+
+// String 返回字符字面量的字符串表示，例如 'a'
+func (cl *CharLiteral) String() string { return "'" + cl.Token.Literal + "'" }
+
+// RegexLiteral 代表正则表达式字面量节点，例如 re"(?P<year>\d{4})"i
+type RegexLiteral struct {
+	Token   token.Token // token.REGEX 词法单元，字面量为从左引号到最后一个标志的原始内容
+	Pattern string      // 正则表达式模式
+	Flags   string      // 标志，可以是 i、m、s 和 U 的组合
+}
+
+// expressionNode 实现 Expression 接口，用于标识 RegexLiteral 是一个表达式节点
+func (rl *RegexLiteral) expressionNode() {}
+
+// TokenLiteral 返回正则表达式字面量的词法字面量
+func (rl *RegexLiteral) TokenLiteral() string { return rl.Token.Literal }
+
+// String 返回正则表达式字面量的字符串表示，例如 re"[a-z]+"i
+func (rl *RegexLiteral) String() string { return "re" + rl.Token.Literal }
+
+// MemberExpression 代表成员访问表达式节点，例如 lib.name
+type MemberExpression struct {
+	Token    token.Token // '.' 或 "?." 词法单元
+	Object   Expression  // 被访问的对象
+	Property *Identifier // 成员名
+	Optional bool        // 是否为 "?."，对象为 nil 时结果为 nil
+}
+
+// expressionNode 实现 Expression 接口，用于标识 MemberExpression 是一个表达式节点
+func (me *MemberExpression) expressionNode() {}
+
+// TokenLiteral 返回成员访问表达式的词法字面量
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+
+// String 返回成员访问表达式的字符串表示，例如 "lib.name"
+func (me *MemberExpression) String() string {
+	return me.Object.String() + me.Token.Literal + me.Property.String()
+}
+
+// IndexExpression 代表下标表达式节点，例如 s[0] 或 s?[0]
+type IndexExpression struct {
+	Token    token.Token // '[' 或 "?[" 词法单元
+	Left     Expression  // 被访问的对象
+	Index    Expression  // 下标
+	Optional bool        // 是否为 "?["，对象为 nil 时结果为 nil
+}
+
+// expressionNode 实现 Expression 接口，用于标识 IndexExpression 是一个表达式节点
+func (ie *IndexExpression) expressionNode() {}
+
+// TokenLiteral 返回下标表达式的词法字面量
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// String 返回下标表达式的字符串表示，例如 "(s[0])"
+func (ie *IndexExpression) String() string {
+	return "(" + ie.Left.String() + ie.Token.Literal + ie.Index.String() + "])"
+}
+
+// ImportStatement 代表 import 语句节点，例如 import "lib/math.pg" as math;
+type ImportStatement struct {
+	Token token.Token    // token.IMPORT 词法单元
+	Path  *StringLiteral // 模块路径
+	Alias *Identifier    // 模块在当前环境中的名字
+}
+
+// statementNode 实现 Statement 接口，用于标识 ImportStatement 是一个语句节点
+func (is *ImportStatement) statementNode() {}
+
+// TokenLiteral 返回 import 语句的词法字面量
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+
+// String 返回 import 语句的字符串表示，例如 "import "lib/math.pg" as math;"
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " " + is.Path.String() + " as " + is.Alias.String() + ";"
+}
+
+// ExportStatement 代表 export 声明节点，例如 export let pi = 3;
+type ExportStatement struct {
+	Token     token.Token // token.EXPORT 词法单元
+	Statement Statement   // 被导出的 let、fn 或 enum 声明
+}
+
+// statementNode 实现 Statement 接口，用于标识 ExportStatement 是一个语句节点
+func (es *ExportStatement) statementNode() {}
+
+// TokenLiteral 返回 export 声明的词法字面量
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+
+// String 返回 export 声明的字符串表示
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+// MacroLiteral 代表宏字面量节点，例如 macro(a, b) { quote(unquote(a) + unquote(b)) }
+type MacroLiteral struct {
+	Token      token.Token     // token.MACRO 词法单元
+	Parameters []*Identifier   // 形参列表，调用时绑定为未求值的 AST
+	Body       *BlockStatement // 宏体，必须返回 quote 得到的 AST
+}
+
+// expressionNode 实现 Expression 接口，用于标识 MacroLiteral 是一个表达式节点
+func (ml *MacroLiteral) expressionNode() {}
+
+// TokenLiteral 返回宏字面量的词法字面量
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+
+// String 返回宏字面量的字符串表示，例如 "macro(a, b) { ... }"
+func (ml *MacroLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String()) // 1. 收集形参名
+	}
+
+	out.WriteString(ml.TokenLiteral())          // 2. 写入 "macro"
+	out.WriteString("(")                        // 3. 写入左括号
+	out.WriteString(strings.Join(params, ", ")) // 4. 写入以逗号分隔的形参
+	out.WriteString(") ")                       // 5. 写入右括号
+	out.WriteString(ml.Body.String())           // 6. 写入宏体
+
+	return out.String()
+}
+
+// InterpolatedString 代表包含插值的字符串节点，例如 "Hello ${name}"
+type InterpolatedString struct {
+	Token token.Token  // token.STRING 词法单元
+	Parts []Expression // 依次排列的文本片段（StringLiteral）和插值表达式
+}
+
+// expressionNode 实现 Expression 接口，用于标识 InterpolatedString 是一个表达式节点
+func (is *InterpolatedString) expressionNode() {}
+
+// TokenLiteral 返回插值字符串的词法字面量
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+
+// String 返回插值字符串的字符串表示，例如 "\"Hello ${name}\""
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"") // 1. 写入左引号
+	for _, part := range is.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Token.Literal) // 2. 文本片段写入未经转义的原始内容
+		} else {
+			out.WriteString("${" + part.String() + "}") // 3. 插值表达式写入 ${...}
+		}
+	}
+	out.WriteString("\"") // 4. 写入右引号
+
+	return out.String()
+}
+
+// RangeExpression 代表区间表达式节点，例如 1..10、1..=10 或 10..0 step -2
+type RangeExpression struct {
+	Token     token.Token // ".." 或 "..=" 词法单元
+	Start     Expression  // 起点
+	End       Expression  // 终点
+	Inclusive bool        // 是否包含终点，即 "..="
+	Step      Expression  // 步长，省略时为 nil
+}
+
+// expressionNode 实现 Expression 接口，用于标识 RangeExpression 是一个表达式节点
+func (re *RangeExpression) expressionNode() {}
+
+// TokenLiteral 返回区间表达式的词法字面量
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+
+// String 返回区间表达式的字符串表示，例如 "(1..10 step 2)"
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String() + re.Token.Literal + re.End.String()) // 1. 写入起点、操作符和终点
+	if re.Step != nil {
+		out.WriteString(" step " + re.Step.String()) // 2. 写入可选的步长
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// ArrayLiteral 代表数组字面量节点，例如 [1, 2, 3]
+type ArrayLiteral struct {
+	Token    token.Token  // '[' 词法单元
+	Elements []Expression // 数组元素
+}
+
+// expressionNode 实现 Expression 接口，用于标识 ArrayLiteral 是一个表达式节点
+func (al *ArrayLiteral) expressionNode() {}
+
+// TokenLiteral 返回数组字面量的词法字面量
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+
+// String 返回数组字面量的字符串表示，例如 "[1, 2, 3]"
+func (al *ArrayLiteral) String() string {
+	elements := []string{}
+	for _, e := range al.Elements {
+		elements = append(elements, e.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// SetLiteral 代表集合字面量节点，例如 #{1, 2, 3}
+type SetLiteral struct {
+	Token    token.Token  // "#{" 词法单元
+	Elements []Expression // 集合元素
+}
+
+// expressionNode 实现 Expression 接口，用于标识 SetLiteral 是一个表达式节点
+func (sl *SetLiteral) expressionNode() {}
+
+// TokenLiteral 返回集合字面量的词法字面量
+func (sl *SetLiteral) TokenLiteral() string { return sl.Token.Literal }
+
+// String 返回集合字面量的字符串表示，例如 "#{1, 2, 3}"
+func (sl *SetLiteral) String() string {
+	elements := []string{}
+	for _, e := range sl.Elements {
+		elements = append(elements, e.String())
+	}
+	return "#{" + strings.Join(elements, ", ") + "}"
+}
+
+// TupleLiteral 代表元组字面量节点，例如 (1, "a", true)；只有一个元素的元组写作 (1,)
+type TupleLiteral struct {
+	Token    token.Token  // '(' 词法单元
+	Elements []Expression // 元组元素
+}
+
+// expressionNode 实现 Expression 接口，用于标识 TupleLiteral 是一个表达式节点
+func (tl *TupleLiteral) expressionNode() {}
+
+// TokenLiteral 返回元组字面量的词法字面量
+func (tl *TupleLiteral) TokenLiteral() string { return tl.Token.Literal }
+
+// String 返回元组字面量的字符串表示，例如 "(1, 2)" 或 "(1,)"
+func (tl *TupleLiteral) String() string {
+	elements := []string{}
+	for _, e := range tl.Elements {
+		elements = append(elements, e.String())
+	}
+	if len(elements) == 1 {
+		return "(" + elements[0] + ",)"
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// SliceExpression 代表切片表达式节点，例如 arr[1:3]、s[:5] 或 arr[::-1]，省略的部分为 nil
+type SliceExpression struct {
+	Token    token.Token // '[' 或 "?[" 词法单元
+	Left     Expression  // 被切片的对象
+	Start    Expression  // 起始下标
+	End      Expression  // 结束下标，不包含在结果中
+	Step     Expression  // 步长
+	Optional bool        // 是否为 "?["，对象为 nil 时结果为 nil
+}
+
+// expressionNode 实现 Expression 接口，用于标识 SliceExpression 是一个表达式节点
+func (se *SliceExpression) expressionNode() {}
+
+// TokenLiteral 返回切片表达式的词法字面量
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+
+// String 返回切片表达式的字符串表示，例如 "(arr[1:3])"
+func (se *SliceExpression) String() string {
+	part := func(e Expression) string {
+		if e == nil {
+			return ""
+		}
+		return e.String()
+	}
+
+	out := part(se.Start) + ":" + part(se.End) // 1. 写入起始和结束下标
+	if se.Step != nil {
+		out += ":" + se.Step.String() // 2. 写入可选的步长
+	}
+	return "(" + se.Left.String() + se.Token.Literal + out + "])"
+}
+
+// ForStatement 代表 for 循环语句节点，例如 for x in 1..10 { ... }
+type ForStatement struct {
+	Token    token.Token     // token.FOR 词法单元
+	Variable *Identifier     // 循环变量，每次迭代都会重新绑定
+	Iterable Expression      // 被遍历的对象
+	Body     *BlockStatement // 循环体
+}
+
+// statementNode 实现 Statement 接口，用于标识 ForStatement 是一个语句节点
+func (fs *ForStatement) statementNode() {}
+
+// TokenLiteral 返回 for 语句的词法字面量
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+
+// String 返回 for 语句的字符串表示，例如 "for x in xs { x }"
+func (fs *ForStatement) String() string {
+	return fs.TokenLiteral() + " " + fs.Variable.String() + " in " + fs.Iterable.String() + " " + fs.Body.String()
+}
+
+// ComprehensionClause 代表推导式中的 for 子句，例如 for (k, v) in pairs if k != "id"
+type ComprehensionClause struct {
+	Token     token.Token   // token.FOR 词法单元
+	Variable  *Identifier   // 循环变量，解构时为 nil
+	Names     []*Identifier // 解构的循环变量，例如 for (k, v) in pairs
+	Iterable  Expression    // 被遍历的对象
+	Condition Expression    // 可选的过滤条件，没有时为 nil
+}
+
+// String 返回 for 子句的字符串表示，例如 "for x in xs if (x > 0)"
+func (cc *ComprehensionClause) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cc.Token.Literal + " ")
+	if cc.Variable != nil {
+		out.WriteString(cc.Variable.String())
+	} else {
+		names := []string{}
+		for _, n := range cc.Names {
+			names = append(names, n.String())
+		}
+		out.WriteString("(" + strings.Join(names, ", ") + ")")
+	}
+	out.WriteString(" in " + cc.Iterable.String())
+	if cc.Condition != nil {
+		out.WriteString(" if " + cc.Condition.String())
+	}
+
+	return out.String()
+}
+
+// ListComprehension 代表数组推导式节点，例如 [x * x for x in xs if x % 2 == 0]
+type ListComprehension struct {
+	Token   token.Token          // '[' 词法单元
+	Element Expression           // 每次迭代产生的元素
+	Clause  *ComprehensionClause // for 子句
+}
+
+// expressionNode 实现 Expression 接口，用于标识 ListComprehension 是一个表达式节点
+func (lc *ListComprehension) expressionNode() {}
+
+// TokenLiteral 返回数组推导式的词法字面量
+func (lc *ListComprehension) TokenLiteral() string { return lc.Token.Literal }
+
+// String 返回数组推导式的字符串表示
+func (lc *ListComprehension) String() string {
+	return "[" + lc.Element.String() + " " + lc.Clause.String() + "]"
+}
+
+// HashComprehension 代表哈希推导式节点，例如 {k: v for (k, v) in pairs}
+type HashComprehension struct {
+	Token  token.Token          // '{' 词法单元
+	Key    Expression           // 每次迭代产生的键
+	Value  Expression           // 每次迭代产生的值
+	Clause *ComprehensionClause // for 子句
+}
+
+// expressionNode 实现 Expression 接口，用于标识 HashComprehension 是一个表达式节点
+func (hc *HashComprehension) expressionNode() {}
+
+// TokenLiteral 返回哈希推导式的词法字面量
+func (hc *HashComprehension) TokenLiteral() string { return hc.Token.Literal }
+
+// String 返回哈希推导式的字符串表示
+func (hc *HashComprehension) String() string {
+	return "{" + hc.Key.String() + ": " + hc.Value.String() + " " + hc.Clause.String() + "}"
+}
+
+// KeywordArgument 代表调用中按名字传入的实参节点，例如 connect(port: 9000) 中的 port: 9000
+type KeywordArgument struct {
+	Token token.Token // 形参名的词法单元
+	Name  *Identifier // 形参名
+	Value Expression  // 实参
+}
+
+// expressionNode 实现 Expression 接口，用于标识 KeywordArgument 是一个表达式节点
+func (ka *KeywordArgument) expressionNode() {}
+
+// TokenLiteral 返回关键字实参的词法字面量
+func (ka *KeywordArgument) TokenLiteral() string { return ka.Token.Literal }
+
+// String 返回关键字实参的字符串表示，例如 "port: 9000"
+func (ka *KeywordArgument) String() string { return ka.Name.String() + ": " + ka.Value.String() }
+
+// SpreadExpression 代表展开表达式节点，例如 f(...args)、[...a, ...b] 或 {...m}，只能出现在实参、数组和哈希字面量中
+type SpreadExpression struct {
+	Token token.Token // "..." 词法单元
+	Value Expression  // 被展开的对象
+}
+
+// expressionNode 实现 Expression 接口，用于标识 SpreadExpression 是一个表达式节点
+func (se *SpreadExpression) expressionNode() {}
+
+// TokenLiteral 返回展开表达式的词法字面量
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+
+// String 返回展开表达式的字符串表示，例如 "...args"
+func (se *SpreadExpression) String() string { return se.Token.Literal + se.Value.String() }
+
+// HashEntry 代表哈希字面量中的一项，Key 为 SpreadExpression 时表示展开另一个哈希，此时 Value 为 nil
+type HashEntry struct {
+	Key   Expression // 键，或被展开的哈希
+	Value Expression // 值
+}
+
+// HashLiteral 代表哈希字面量节点，例如 {"a": 1, ...m}，各项按书写顺序求值，后出现的键覆盖先出现的键
+type HashLiteral struct {
+	Token   token.Token  // '{' 词法单元
+	Entries []*HashEntry // 哈希中的各项
+}
+
+// expressionNode 实现 Expression 接口，用于标识 HashLiteral 是一个表达式节点
+func (hl *HashLiteral) expressionNode() {}
+
+// TokenLiteral 返回哈希字面量的词法字面量
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+
+// String 返回哈希字面量的字符串表示，例如 "{a: 1, ...m}"
+func (hl *HashLiteral) String() string {
+	entries := []string{}
+	for _, e := range hl.Entries {
+		if e.Value == nil {
+			entries = append(entries, e.Key.String()) // 1. 展开项只有被展开的表达式
+		} else {
+			entries = append(entries, e.Key.String()+": "+e.Value.String()) // 2. 普通项写入键和值
+		}
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// IfExpression 代表条件表达式节点，例如 if x > 0 { x } else { -x }，else if 被解析为只包含另一个 IfExpression 的语句块
+type IfExpression struct {
+	Token       token.Token     // token.IF 词法单元
+	Condition   Expression      // 条件
+	Consequence *BlockStatement // 条件成立时执行的语句块
+	Alternative *BlockStatement // 条件不成立时执行的语句块，没有 else 时为 nil
+}
+
+// expressionNode 实现 Expression 接口，用于标识 IfExpression 是一个表达式节点
+func (ie *IfExpression) expressionNode() {}
+
+// TokenLiteral 返回条件表达式的词法字面量
+func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// String 返回条件表达式的字符串表示，例如 "if (x > 0) { x } else { (-x) }"
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if " + ie.Condition.String() + " ") // 1. 写入条件
+	out.WriteString(ie.Consequence.String())             // 2. 写入条件成立时的语句块
+	if ie.Alternative != nil {
+		out.WriteString(" else " + ie.Alternative.String()) // 3. 写入可选的 else 语句块
+	}
+
+	return out.String()
+}
+
+// MatchCase 代表 match 表达式中的一个分支，例如 case Circle(r) { ... } 或 case Empty { ... }
+type MatchCase struct {
+	Token   token.Token     // token.CASE 词法单元
+	Variant *Identifier     // 匹配的变体名称
+	Names   []*Identifier   // 按顺序绑定变体字段的变量名，_ 表示忽略该字段；没有括号时为 nil，只比较变体
+	Body    *BlockStatement // 匹配成功时执行的语句块
+}
+
+// String 返回分支的字符串表示
+func (mc *MatchCase) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(mc.Token.Literal + " " + mc.Variant.String()) // 1. 写入 "case " 和变体名称
+	if mc.Names != nil {
+		names := []string{}
+		for _, n := range mc.Names {
+			names = append(names, n.String())
+		}
+		out.WriteString("(" + strings.Join(names, ", ") + ")") // 2. 写入绑定字段的变量名
+	}
+	out.WriteString(" " + mc.Body.String()) // 3. 写入语句块
+
+	return out.String()
+}
+
+// MatchExpression 代表 match 表达式节点，按枚举值的变体选择分支，例如 match s { case Circle(r) { r * r } default { 0 } }
+type MatchExpression struct {
+	Token   token.Token     // token.MATCH 词法单元
+	Subject Expression      // 被匹配的值
+	Cases   []*MatchCase    // 按顺序尝试的分支
+	Default *BlockStatement // 没有分支匹配时执行的语句块，可为空
+}
+
+// expressionNode 实现 Expression 接口，用于标识 MatchExpression 是一个表达式节点
+func (me *MatchExpression) expressionNode() {}
+
+// TokenLiteral 返回 match 表达式的词法字面量
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+
+// String 返回 match 表达式的字符串表示
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	cases := []string{}
+	for _, c := range me.Cases {
+		cases = append(cases, c.String()) // 1. 收集每个分支的字符串表示
+	}
+	if me.Default != nil {
+		cases = append(cases, "default "+me.Default.String()) // 2. 写入 default 分支
+	}
+
+	out.WriteString("match " + me.Subject.String() + " { ") // 3. 写入被匹配的值
+	out.WriteString(strings.Join(cases, " "))               // 4. 写入所有分支
+	out.WriteString(" }")                                   // 5. 写入右大括号
+
+	return out.String()
+}
+
+// TypeExpression 接口表示类型标注中的类型，类型检查器根据它得到静态类型
+type TypeExpression interface {
+	Node
+	typeNode()
+}
+
+// NamedType 代表以名字表示的类型，例如 int、string、any、类型参数或枚举名，泛型枚举可以带有类型实参，例如 Pair<int, string>
+type NamedType struct {
+	Token     token.Token      // 类型名的词法单元
+	Name      string           // 类型名
+	Arguments []TypeExpression // 类型实参，没有时为空
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 NamedType 是一个类型节点
+func (nt *NamedType) typeNode() {}
+
+// TokenLiteral 返回类型名的词法字面量
+func (nt *NamedType) TokenLiteral() string { return nt.Token.Literal }
+
+// String 返回类型名和类型实参
+func (nt *NamedType) String() string {
+	if len(nt.Arguments) == 0 {
+		return nt.Name
+	}
+	args := []string{}
+	for _, a := range nt.Arguments {
+		args = append(args, a.String())
+	}
+	return nt.Name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// ArrayType 代表数组类型，例如 [int]
+type ArrayType struct {
+	Token   token.Token    // '[' 词法单元
+	Element TypeExpression // 元素类型
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 ArrayType 是一个类型节点
+func (at *ArrayType) typeNode() {}
+
+// TokenLiteral 返回数组类型的词法字面量
+func (at *ArrayType) TokenLiteral() string { return at.Token.Literal }
+
+// String 返回数组类型的字符串表示，例如 "[int]"
+func (at *ArrayType) String() string { return "[" + at.Element.String() + "]" }
+
+// HashType 代表哈希类型，例如 {string: int}
+type HashType struct {
+	Token token.Token    // '{' 词法单元
+	Key   TypeExpression // 键的类型
+	Value TypeExpression // 值的类型
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 HashType 是一个类型节点
+func (ht *HashType) typeNode() {}
+
+// TokenLiteral 返回哈希类型的词法字面量
+func (ht *HashType) TokenLiteral() string { return ht.Token.Literal }
+
+// String 返回哈希类型的字符串表示，例如 "{string: int}"
+func (ht *HashType) String() string { return "{" + ht.Key.String() + ": " + ht.Value.String() + "}" }
+
+// SetType 代表集合类型，例如 #{int}
+type SetType struct {
+	Token   token.Token    // "#{" 词法单元
+	Element TypeExpression // 元素类型
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 SetType 是一个类型节点
+func (st *SetType) typeNode() {}
+
+// TokenLiteral 返回集合类型的词法字面量
+func (st *SetType) TokenLiteral() string { return st.Token.Literal }
+
+// String 返回集合类型的字符串表示，例如 "#{int}"
+func (st *SetType) String() string { return "#{" + st.Element.String() + "}" }
+
+// TupleType 代表元组类型，例如 (int, string)
+type TupleType struct {
+	Token    token.Token      // '(' 词法单元
+	Elements []TypeExpression // 各元素的类型
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 TupleType 是一个类型节点
+func (tt *TupleType) typeNode() {}
+
+// TokenLiteral 返回元组类型的词法字面量
+func (tt *TupleType) TokenLiteral() string { return tt.Token.Literal }
+
+// String 返回元组类型的字符串表示，例如 "(int, string)"
+func (tt *TupleType) String() string {
+	elements := []string{}
+	for _, e := range tt.Elements {
+		elements = append(elements, e.String())
+	}
+	if len(elements) == 1 {
+		return "(" + elements[0] + ",)"
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// FunctionType 代表函数类型，例如 fn(int, int) -> int
+type FunctionType struct {
+	Token      token.Token      // token.FUNCTION 词法单元
+	Parameters []TypeExpression // 各形参的类型
+	Return     TypeExpression   // 返回值的类型，省略时为 nil
+}
+
+// typeNode 实现 TypeExpression 接口，用于标识 FunctionType 是一个类型节点
+func (ft *FunctionType) typeNode() {}
+
+// TokenLiteral 返回函数类型的词法字面量
+func (ft *FunctionType) TokenLiteral() string { return ft.Token.Literal }
+
+// String 返回函数类型的字符串表示，例如 "fn(int, int) -> int"
+func (ft *FunctionType) String() string {
+	params := []string{}
+	for _, p := range ft.Parameters {
+		params = append(params, p.String())
+	}
+	out := "fn(" + strings.Join(params, ", ") + ")"
+	if ft.Return != nil {
+		out += " -> " + ft.Return.String()
+	}
+	return out
+}
+
+// typeParamsString 返回类型参数列表的字符串表示，例如 "<A, B>"，没有类型参数时为空字符串
+func typeParamsString(params []*Identifier) string {
+	if len(params) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, p := range params {
+		names = append(names, p.String())
+	}
+	return "<" + strings.Join(names, ", ") + ">"
+}
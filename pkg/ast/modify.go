@@ -0,0 +1,337 @@
+package ast
+
+// ModifierFunc 定义节点修改函数，返回值将替换传入的节点
+type ModifierFunc func(Node) Node
+
+// Modify 自底向上遍历 AST，先修改子节点，再把 modifier 应用于节点本身。
+// 被修改路径上的节点都会被复制，原来的 AST 保持不变，因此同一段 AST 可以被多次修改
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+
+	case *Program:
+		copied := *node
+		copied.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&copied)
+
+	case *ExpressionStatement:
+		copied := *node
+		copied.Expression = modifyExpression(node.Expression, modifier)
+		return modifier(&copied)
+
+	case *BlockStatement:
+		copied := *node
+		copied.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&copied)
+
+	case *LetStatement:
+		copied := *node
+		copied.Name = modifyIdentifier(node.Name, modifier)
+		if node.Names != nil {
+			copied.Names = modifyIdentifiers(node.Names, modifier)
+		}
+		copied.Value = modifyExpression(node.Value, modifier)
+		return modifier(&copied)
+
+	case *ReturnStatement:
+		copied := *node
+		copied.ReturnValue = modifyExpression(node.ReturnValue, modifier)
+		return modifier(&copied)
+
+	case *ThrowStatement:
+		copied := *node
+		copied.Value = modifyExpression(node.Value, modifier)
+		return modifier(&copied)
+
+	case *YieldStatement:
+		copied := *node
+		copied.Value = modifyExpression(node.Value, modifier)
+		return modifier(&copied)
+
+	case *DeferStatement:
+		copied := *node
+		if call, ok := Modify(node.Call, modifier).(*CallExpression); ok {
+			copied.Call = call
+		}
+		return modifier(&copied)
+
+	case *TryStatement:
+		copied := *node
+		copied.Block = modifyBlock(node.Block, modifier)
+		copied.CatchParam = modifyIdentifier(node.CatchParam, modifier)
+		copied.Catch = modifyBlock(node.Catch, modifier)
+		copied.Finally = modifyBlock(node.Finally, modifier)
+		return modifier(&copied)
+
+	case *FunctionStatement:
+		copied := *node
+		if fl, ok := Modify(node.Function, modifier).(*FunctionLiteral); ok {
+			copied.Function = fl
+		}
+		return modifier(&copied)
+
+	case *ImplStatement:
+		copied := *node
+		copied.Methods = make([]*FunctionLiteral, len(node.Methods))
+		for i, m := range node.Methods {
+			copied.Methods[i] = m
+			if fl, ok := Modify(m, modifier).(*FunctionLiteral); ok {
+				copied.Methods[i] = fl
+			}
+		}
+		return modifier(&copied)
+
+	case *ExportStatement:
+		copied := *node
+		if stmt, ok := Modify(node.Statement, modifier).(Statement); ok {
+			copied.Statement = stmt
+		}
+		return modifier(&copied)
+
+	case *ForStatement:
+		copied := *node
+		copied.Variable = modifyIdentifier(node.Variable, modifier)
+		copied.Iterable = modifyExpression(node.Iterable, modifier)
+		copied.Body = modifyBlock(node.Body, modifier)
+		return modifier(&copied)
+
+	case *SelectStatement:
+		copied := *node
+		copied.Cases = make([]*SelectCase, len(node.Cases))
+		for i, sc := range node.Cases {
+			c := *sc
+			c.Name = modifyIdentifier(sc.Name, modifier)
+			if call, ok := Modify(sc.Comm, modifier).(*CallExpression); ok {
+				c.Comm = call
+			}
+			c.Body = modifyBlock(sc.Body, modifier)
+			copied.Cases[i] = &c
+		}
+		copied.Default = modifyBlock(node.Default, modifier)
+		return modifier(&copied)
+
+	case *PrefixExpression:
+		copied := *node
+		copied.Right = modifyExpression(node.Right, modifier)
+		return modifier(&copied)
+
+	case *MatchExpression:
+		copied := *node
+		copied.Subject = modifyExpression(node.Subject, modifier)
+		copied.Cases = make([]*MatchCase, len(node.Cases))
+		for i, mc := range node.Cases {
+			c := *mc
+			c.Variant = modifyIdentifier(mc.Variant, modifier)
+			if mc.Names != nil {
+				c.Names = modifyIdentifiers(mc.Names, modifier)
+			}
+			c.Body = modifyBlock(mc.Body, modifier)
+			copied.Cases[i] = &c
+		}
+		copied.Default = modifyBlock(node.Default, modifier)
+		return modifier(&copied)
+
+	case *InfixExpression:
+		copied := *node
+		copied.Left = modifyExpression(node.Left, modifier)
+		copied.Right = modifyExpression(node.Right, modifier)
+		return modifier(&copied)
+
+	case *PostfixExpression:
+		copied := *node
+		copied.Left = modifyExpression(node.Left, modifier)
+		return modifier(&copied)
+
+	case *CallExpression:
+		copied := *node
+		copied.Function = modifyExpression(node.Function, modifier)
+		copied.Arguments = modifyExpressions(node.Arguments, modifier)
+		return modifier(&copied)
+
+	case *MemberExpression:
+		copied := *node
+		copied.Object = modifyExpression(node.Object, modifier)
+		return modifier(&copied)
+
+	case *IndexExpression:
+		copied := *node
+		copied.Left = modifyExpression(node.Left, modifier)
+		copied.Index = modifyExpression(node.Index, modifier)
+		return modifier(&copied)
+
+	case *SliceExpression:
+		copied := *node
+		copied.Left = modifyExpression(node.Left, modifier)
+		copied.Start = modifyExpression(node.Start, modifier)
+		copied.End = modifyExpression(node.End, modifier)
+		copied.Step = modifyExpression(node.Step, modifier)
+		return modifier(&copied)
+
+	case *RangeExpression:
+		copied := *node
+		copied.Start = modifyExpression(node.Start, modifier)
+		copied.End = modifyExpression(node.End, modifier)
+		copied.Step = modifyExpression(node.Step, modifier)
+		return modifier(&copied)
+
+	case *KeywordArgument:
+		copied := *node
+		copied.Value = modifyExpression(node.Value, modifier)
+		return modifier(&copied)
+
+	case *SpreadExpression:
+		copied := *node
+		copied.Value = modifyExpression(node.Value, modifier)
+		return modifier(&copied)
+
+	case *HashLiteral:
+		copied := *node
+		copied.Entries = make([]*HashEntry, len(node.Entries))
+		for i, e := range node.Entries {
+			copied.Entries[i] = &HashEntry{
+				Key:   modifyExpression(e.Key, modifier),
+				Value: modifyExpression(e.Value, modifier),
+			}
+		}
+		return modifier(&copied)
+
+	case *IfExpression:
+		copied := *node
+		copied.Condition = modifyExpression(node.Condition, modifier)
+		copied.Consequence = modifyBlock(node.Consequence, modifier)
+		copied.Alternative = modifyBlock(node.Alternative, modifier)
+		return modifier(&copied)
+
+	case *ListComprehension:
+		copied := *node
+		copied.Element = modifyExpression(node.Element, modifier)
+		copied.Clause = modifyClause(node.Clause, modifier)
+		return modifier(&copied)
+
+	case *HashComprehension:
+		copied := *node
+		copied.Key = modifyExpression(node.Key, modifier)
+		copied.Value = modifyExpression(node.Value, modifier)
+		copied.Clause = modifyClause(node.Clause, modifier)
+		return modifier(&copied)
+
+	case *ArrayLiteral:
+		copied := *node
+		copied.Elements = modifyExpressions(node.Elements, modifier)
+		return modifier(&copied)
+
+	case *SetLiteral:
+		copied := *node
+		copied.Elements = modifyExpressions(node.Elements, modifier)
+		return modifier(&copied)
+
+	case *TupleLiteral:
+		copied := *node
+		copied.Elements = modifyExpressions(node.Elements, modifier)
+		return modifier(&copied)
+
+	case *InterpolatedString:
+		copied := *node
+		copied.Parts = modifyExpressions(node.Parts, modifier)
+		return modifier(&copied)
+
+	case *SpawnExpression:
+		copied := *node
+		if call, ok := Modify(node.Call, modifier).(*CallExpression); ok {
+			copied.Call = call
+		}
+		return modifier(&copied)
+
+	case *FunctionLiteral:
+		copied := *node
+		copied.Parameters = modifyIdentifiers(node.Parameters, modifier)
+		if node.Defaults != nil {
+			copied.Defaults = modifyExpressions(node.Defaults, modifier)
+		}
+		copied.Rest = modifyIdentifier(node.Rest, modifier)
+		copied.Body = modifyBlock(node.Body, modifier)
+		return modifier(&copied)
+
+	case *MacroLiteral:
+		copied := *node
+		copied.Parameters = modifyIdentifiers(node.Parameters, modifier)
+		copied.Body = modifyBlock(node.Body, modifier)
+		return modifier(&copied)
+
+	default:
+		return modifier(node) // 叶子节点以及不含子表达式的声明直接交给 modifier
+	}
+}
+
+// modifyStatements 依次修改语句列表，返回新的列表
+func modifyStatements(stmts []Statement, modifier ModifierFunc) []Statement {
+	modified := make([]Statement, 0, len(stmts))
+	for _, s := range stmts {
+		if stmt, ok := Modify(s, modifier).(Statement); ok {
+			modified = append(modified, stmt)
+		}
+	}
+	return modified
+}
+
+// modifyExpressions 依次修改表达式列表，返回新的列表
+func modifyExpressions(exps []Expression, modifier ModifierFunc) []Expression {
+	modified := make([]Expression, len(exps))
+	for i, e := range exps {
+		modified[i] = modifyExpression(e, modifier)
+	}
+	return modified
+}
+
+// modifyIdentifiers 依次修改标识符列表，返回新的列表
+func modifyIdentifiers(idents []*Identifier, modifier ModifierFunc) []*Identifier {
+	modified := make([]*Identifier, len(idents))
+	for i, ident := range idents {
+		modified[i] = modifyIdentifier(ident, modifier)
+	}
+	return modified
+}
+
+// modifyExpression 修改一个表达式，结果不再是表达式时保留原表达式
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+	if modified, ok := Modify(exp, modifier).(Expression); ok {
+		return modified
+	}
+	return exp
+}
+
+// modifyIdentifier 修改一个标识符，结果不再是标识符时保留原标识符
+func modifyIdentifier(ident *Identifier, modifier ModifierFunc) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	if modified, ok := Modify(ident, modifier).(*Identifier); ok {
+		return modified
+	}
+	return ident
+}
+
+// modifyClause 修改推导式的 for 子句，返回新的子句
+func modifyClause(clause *ComprehensionClause, modifier ModifierFunc) *ComprehensionClause {
+	copied := *clause
+	copied.Variable = modifyIdentifier(clause.Variable, modifier)
+	if clause.Names != nil {
+		copied.Names = modifyIdentifiers(clause.Names, modifier)
+	}
+	copied.Iterable = modifyExpression(clause.Iterable, modifier)
+	copied.Condition = modifyExpression(clause.Condition, modifier)
+	return &copied
+}
+
+// modifyBlock 修改一个语句块，结果不再是语句块时保留原语句块
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	if modified, ok := Modify(block, modifier).(*BlockStatement); ok {
+		return modified
+	}
+	return block
+}
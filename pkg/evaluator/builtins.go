@@ -0,0 +1,109 @@
+package evaluator
+
+import (
+	"punyGo/pkg/object"
+)
+
+// resultEnum 是内置的 Result 枚举，Ok 表示成功的值，Err 表示失败的原因
+var resultEnum = &object.Enum{Name: "Result"}
+
+// Result 枚举的两个变体，'?' 操作符通过它们识别 Result 值
+var (
+	OK  = &object.Variant{Enum: resultEnum, Name: "Ok", Fields: []string{"value"}}
+	ERR = &object.Variant{Enum: resultEnum, Name: "Err", Fields: []string{"error"}}
+)
+
+func init() {
+	resultEnum.Variants = []*object.Variant{OK, ERR}
+
+	for name, builtin := range concurrencyBuiltins { // 注册与并发相关的内置函数
+		builtins[name] = builtin
+	}
+	for name, builtin := range conversionBuiltins { // 注册字符串、字节串和字符之间的转换函数
+		builtins[name] = builtin
+	}
+}
+
+// builtins 保存所有内置对象，在环境中找不到标识符时查找
+var builtins = map[string]object.Object{
+	"Result": resultEnum,
+	"Ok":     OK,
+	"Err":    ERR,
+	"next": &object.Builtin{
+		Name: "next",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 { // 1. next 只接受一个生成器
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for next: want=1, got=%d", len(args))
+			}
+			gen, ok := args[0].(*object.Generator)
+			if !ok {
+				return newError(object.TYPE_ERROR, "argument to next must be GENERATOR, got %s", args[0].Type())
+			}
+			return nextGenerator(gen) // 2. 恢复生成器并返回下一个值
+		},
+	},
+	"divmod": &object.Builtin{
+		Name: "divmod",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for divmod: want=2, got=%d", len(args))
+			}
+			a, ok1 := args[0].(*object.Integer)
+			b, ok2 := args[1].(*object.Integer)
+			if !ok1 || !ok2 {
+				return newError(object.TYPE_ERROR, "arguments to divmod must be INTEGER, got %s and %s", args[0].Type(), args[1].Type())
+			}
+			if b.Value == 0 {
+				return newError(object.ARGUMENT_ERROR, "divmod by zero")
+			}
+			return &object.Tuple{Elements: []object.Object{ // 返回商和余数组成的元组
+				&object.Integer{Value: a.Value / b.Value},
+				&object.Integer{Value: a.Value % b.Value},
+			}}
+		},
+	},
+	"len": &object.Builtin{
+		Name: "len",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for len: want=1, got=%d", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.String:
+				return &object.Integer{Value: int64(len([]rune(arg.Value)))} // 1. 字符串按字符计数
+			case *object.Bytes:
+				return &object.Integer{Value: int64(len(arg.Value))} // 1.1. 字节串按字节计数
+			case *object.Array:
+				return &object.Integer{Value: int64(len(arg.Elements))} // 2. 数组元素个数
+			case *object.Tuple:
+				return &object.Integer{Value: int64(len(arg.Elements))} // 2.1. 元组元素个数
+			case *object.Range:
+				return &object.Integer{Value: arg.Len()} // 3. 区间元素个数，无需展开区间
+			case *object.Hash:
+				return &object.Integer{Value: int64(len(arg.Keys))} // 4. 哈希键值对个数
+			case *object.Set:
+				return &object.Integer{Value: int64(len(arg.Keys))} // 5. 集合元素个数
+			default:
+				return newError(object.TYPE_ERROR, "argument to len not supported, got %s", arg.Type())
+			}
+		},
+	},
+	"items": &object.Builtin{
+		Name: "items",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for items: want=1, got=%d", len(args))
+			}
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newError(object.TYPE_ERROR, "argument to items must be HASH, got %s", args[0].Type())
+			}
+			pairs := make([]object.Object, 0, len(hash.Keys))
+			for _, k := range hash.Keys { // 按插入顺序返回 (键, 值) 元组的数组
+				pair := hash.Pairs[k]
+				pairs = append(pairs, &object.Tuple{Elements: []object.Object{pair.Key, pair.Value}})
+			}
+			return &object.Array{Elements: pairs}
+		},
+	},
+}
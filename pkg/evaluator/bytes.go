@@ -0,0 +1,99 @@
+package evaluator
+
+import (
+	"unicode/utf8"
+
+	"punyGo/pkg/object"
+)
+
+// conversionBuiltins 是在字符串、字节串和字符之间转换的内置函数
+var conversionBuiltins = map[string]*object.Builtin{
+	"bytes": {
+		Name: "bytes",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for bytes: want=1, got=%d", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Bytes:
+				return &object.Bytes{Value: append([]byte{}, arg.Value...)} // 1. 复制字节串
+			case *object.String:
+				return &object.Bytes{Value: []byte(arg.Value)} // 2. 字符串按 UTF-8 编码
+			case *object.Char:
+				return &object.Bytes{Value: []byte(string(arg.Value))} // 3. 字符按 UTF-8 编码
+			}
+			value := []byte{}
+			result := iterate(args[0], func(e object.Object) object.Object { // 4. 由 0 到 255 的整数序列创建字节串
+				n, ok := e.(*object.Integer)
+				if !ok || n.Value < 0 || n.Value > 255 {
+					return newError(object.TYPE_ERROR, "bytes must be built from integers in 0..=255, got %s", e.Inspect())
+				}
+				value = append(value, byte(n.Value))
+				return nil
+			})
+			if result != nil {
+				return result
+			}
+			return &object.Bytes{Value: value}
+		},
+	},
+	"str": {
+		Name: "str",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for str: want=1, got=%d", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Bytes:
+				if !utf8.Valid(arg.Value) { // 1. 字节串必须是合法的 UTF-8 编码
+					return newError(object.ARGUMENT_ERROR, "bytes are not valid UTF-8: %s", arg.Inspect())
+				}
+				return &object.String{Value: string(arg.Value)}
+			case *object.Char:
+				return &object.String{Value: string(arg.Value)} // 2. 字符转换为只含该字符的字符串
+			case *object.String:
+				return arg
+			default:
+				return &object.String{Value: arg.Inspect()} // 3. 其他对象使用其字符串表示
+			}
+		},
+	},
+	"char": {
+		Name: "char",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for char: want=1, got=%d", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Char:
+				return arg
+			case *object.Integer:
+				if arg.Value < 0 || arg.Value > utf8.MaxRune || !utf8.ValidRune(rune(arg.Value)) { // 1. 整数必须是合法的码点
+					return newError(object.ARGUMENT_ERROR, "invalid code point: %d", arg.Value)
+				}
+				return &object.Char{Value: rune(arg.Value)}
+			case *object.String:
+				ch, size := utf8.DecodeRuneInString(arg.Value)
+				if arg.Value == "" || size != len(arg.Value) { // 2. 字符串必须恰好包含一个字符
+					return newError(object.ARGUMENT_ERROR, "char needs a string of exactly one character, got %q", arg.Value)
+				}
+				return &object.Char{Value: ch}
+			default:
+				return newError(object.TYPE_ERROR, "argument to char not supported, got %s", arg.Type())
+			}
+		},
+	},
+	"ord": {
+		Name: "ord",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for ord: want=1, got=%d", len(args))
+			}
+			ch, ok := args[0].(*object.Char)
+			if !ok {
+				return newError(object.TYPE_ERROR, "argument to ord must be CHAR, got %s", args[0].Type())
+			}
+			return &object.Integer{Value: int64(ch.Value)} // 返回字符的码点
+		},
+	},
+}
@@ -0,0 +1,82 @@
+package evaluator
+
+import (
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// evalListComprehension 评估数组推导式，依次收集每次迭代产生的元素
+func evalListComprehension(node *ast.ListComprehension, env *object.Environment) object.Object {
+	elements := []object.Object{}
+
+	result := evalComprehension(node.Clause, env, func(iterEnv *object.Environment) object.Object {
+		elem := Eval(node.Element, iterEnv)
+		if isAbrupt(elem) {
+			return elem
+		}
+		elements = append(elements, elem)
+		return nil
+	})
+	if result != nil {
+		return result
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// evalHashComprehension 评估哈希推导式，后产生的键覆盖先产生的键
+func evalHashComprehension(node *ast.HashComprehension, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	result := evalComprehension(node.Clause, env, func(iterEnv *object.Environment) object.Object {
+		key := Eval(node.Key, iterEnv)
+		if isAbrupt(key) {
+			return key
+		}
+		hashKey, ok := hashable(key)
+		if !ok {
+			return newError(object.TYPE_ERROR, "unusable as hash key: %s", key.Type())
+		}
+		val := Eval(node.Value, iterEnv)
+		if isAbrupt(val) {
+			return val
+		}
+		hash.Set(hashKey, val)
+		return nil
+	})
+	if result != nil {
+		return result
+	}
+
+	return hash
+}
+
+// evalComprehension 执行推导式的 for 子句。与 for 循环相同，每次迭代都在新的环境中绑定循环变量，
+// 因此循环变量不会泄漏到推导式之外；过滤条件成立时才调用 emit
+func evalComprehension(clause *ast.ComprehensionClause, env *object.Environment, emit func(*object.Environment) object.Object) object.Object {
+	iterable := Eval(clause.Iterable, env) // 1. 在外层环境中评估被遍历的对象
+	if isAbrupt(iterable) {
+		return iterable
+	}
+
+	return iterate(iterable, func(item object.Object) object.Object {
+		iterEnv := object.NewEnvironment(env) // 2. 绑定循环变量
+		if clause.Variable != nil {
+			iterEnv.Set(clause.Variable.Value, item)
+		} else if result := bindNames(clause.Names, item, iterEnv); isAbrupt(result) {
+			return result
+		}
+
+		if clause.Condition != nil { // 3. 过滤不满足条件的元素
+			cond := Eval(clause.Condition, iterEnv)
+			if isAbrupt(cond) {
+				return cond
+			}
+			if !isTruthy(cond) {
+				return nil
+			}
+		}
+
+		return emit(iterEnv) // 4. 产生元素
+	})
+}
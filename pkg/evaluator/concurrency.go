@@ -0,0 +1,228 @@
+package evaluator
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// 以错误结束、尚未被 wait 的任务，按结束的顺序排列，程序退出时报告
+var (
+	taskMu     sync.Mutex
+	unobserved []*object.Task
+)
+
+// UnobservedTaskErrors 返回已经以错误结束、却没有被 wait 观察到的任务的错误。
+// 程序退出时调用它，以免任务中的错误被悄悄丢弃；仍在运行的任务不在其中
+func UnobservedTaskErrors() []*object.Error {
+	taskMu.Lock()
+	defer taskMu.Unlock()
+
+	errs := []*object.Error{}
+	for _, task := range unobserved {
+		errs = append(errs, task.Result.(*object.Error))
+	}
+	return errs
+}
+
+// evalSpawnExpression 在当前任务中评估被调用对象和实参，然后在新的 goroutine 中执行调用。
+// 调用产生的错误不会影响启动它的任务，而是保存在任务对象中，由 wait 重新抛出
+func evalSpawnExpression(node *ast.SpawnExpression, env *object.Environment) object.Object {
+	function := Eval(node.Call.Function, env) // 1. 评估被调用的表达式
+	if isAbrupt(function) {
+		return function
+	}
+	args, kwargs, abrupt := evalCallArguments(node.Call.Arguments, env) // 2. 依次评估实参
+	if abrupt != nil {
+		return abrupt
+	}
+
+	task := &object.Task{Done: make(chan struct{})}
+	go func() {
+		task.Result = applyFunction(function, args, kwargs) // 3. 在新的 goroutine 中执行调用
+		if _, ok := task.Result.(*object.Error); ok {       // 4. 记录出错的任务，直到它被 wait 观察到
+			taskMu.Lock()
+			unobserved = append(unobserved, task)
+			taskMu.Unlock()
+		}
+		close(task.Done) // 5. 通知等待者任务已经结束
+	}()
+
+	return task // 6. 立即返回任务对象
+}
+
+// evalSelectStatement 评估 select 语句，等待任意一个通信分支就绪后执行该分支的语句块
+func evalSelectStatement(node *ast.SelectStatement, env *object.Environment) object.Object {
+	cases := make([]reflect.SelectCase, 0, len(node.Cases)+1)
+
+	for _, sc := range node.Cases { // 1. 评估每个分支的通道和待发送的值
+		args := evalExpressions(sc.Comm.Arguments, env)
+		if len(args) == 1 && isAbrupt(args[0]) {
+			return args[0]
+		}
+		ch, ok := args[0].(*object.Channel)
+		if !ok {
+			return newError(object.TYPE_ERROR, "argument to %s must be CHANNEL, got %s", sc.Comm.Function, args[0].Type())
+		}
+
+		if len(args) == 2 { // 1.1. send(ch, value)
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectSend,
+				Chan: reflect.ValueOf(ch.Ch),
+				Send: reflect.ValueOf(&args[1]).Elem(),
+			})
+		} else { // 1.2. recv(ch)
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.Ch)})
+		}
+	}
+
+	if node.Default != nil { // 2. 存在 default 分支时 select 不会阻塞
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+
+	chosen, recv, recvOK, err := selectChannels(cases) // 3. 等待某个分支就绪
+	if err != nil {
+		return err
+	}
+
+	if chosen == len(node.Cases) { // 4. 选中 default 分支
+		return Eval(node.Default, env)
+	}
+
+	sc := node.Cases[chosen]
+	caseEnv := object.NewEnvironment(env) // 5. 接收到的值只在分支语句块内可见
+	if cases[chosen].Dir == reflect.SelectRecv {
+		if !recvOK { // 5.1. 从已关闭的通道接收
+			return newError(object.CHANNEL_ERROR, "receive from closed channel")
+		}
+		if sc.Name != nil {
+			caseEnv.Set(sc.Name.Value, recv.Interface().(object.Object))
+		}
+	}
+
+	return Eval(sc.Body, caseEnv) // 6. 执行被选中分支的语句块
+}
+
+// selectChannels 执行 reflect.Select，并把向已关闭通道发送引起的 panic 转换为错误对象
+func selectChannels(cases []reflect.SelectCase) (chosen int, recv reflect.Value, recvOK bool, err *object.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError(object.CHANNEL_ERROR, "send on closed channel")
+		}
+	}()
+
+	chosen, recv, recvOK = reflect.Select(cases)
+	return chosen, recv, recvOK, nil
+}
+
+// sendChannel 向通道发送一个值，通道已关闭时返回错误对象
+func sendChannel(ch *object.Channel, val object.Object) (err object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError(object.CHANNEL_ERROR, "send on closed channel")
+		}
+	}()
+
+	ch.Ch <- val // 1. 发送值，通道已满时阻塞
+	return val   // 2. 返回发送的值
+}
+
+// channelArg 检查内置函数的第一个实参是否为通道
+func channelArg(name string, args []object.Object, want int) (*object.Channel, object.Object) {
+	if len(args) != want {
+		return nil, newError(object.ARGUMENT_ERROR, "wrong number of arguments for %s: want=%d, got=%d", name, want, len(args))
+	}
+	ch, ok := args[0].(*object.Channel)
+	if !ok {
+		return nil, newError(object.TYPE_ERROR, "argument to %s must be CHANNEL, got %s", name, args[0].Type())
+	}
+	return ch, nil
+}
+
+// concurrencyBuiltins 是与并发相关的内置函数
+var concurrencyBuiltins = map[string]*object.Builtin{
+	"chan": {
+		Name: "chan",
+		Fn: func(args ...object.Object) object.Object {
+			switch len(args) {
+			case 0:
+				return &object.Channel{Ch: make(chan object.Object)} // 1. chan() 创建无缓冲通道
+			case 1:
+				size, ok := args[0].(*object.Integer)
+				if !ok || size.Value < 0 {
+					return newError(object.TYPE_ERROR, "argument to chan must be a non-negative INTEGER, got %s", args[0].Inspect())
+				}
+				return &object.Channel{Ch: make(chan object.Object, size.Value)} // 2. chan(n) 创建带缓冲的通道
+			default:
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for chan: want=0 or 1, got=%d", len(args))
+			}
+		},
+	},
+	"send": {
+		Name: "send",
+		Fn: func(args ...object.Object) object.Object {
+			ch, err := channelArg("send", args, 2)
+			if err != nil {
+				return err
+			}
+			return sendChannel(ch, args[1])
+		},
+	},
+	"recv": {
+		Name: "recv",
+		Fn: func(args ...object.Object) object.Object {
+			ch, err := channelArg("recv", args, 1)
+			if err != nil {
+				return err
+			}
+			val, ok := <-ch.Ch
+			if !ok { // 1. 已关闭且没有剩余值的通道
+				return newError(object.CHANNEL_ERROR, "receive from closed channel")
+			}
+			return val
+		},
+	},
+	"close": {
+		Name: "close",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) == 1 { // 1. 关闭生成器会结束它的函数体并执行其中的 defer
+				if gen, ok := args[0].(*object.Generator); ok {
+					if err := closeGenerator(gen); err != nil {
+						return err
+					}
+					return gen
+				}
+			}
+			ch, err := channelArg("close", args, 1)
+			if err != nil {
+				return err
+			}
+			if !ch.Close() {
+				return newError(object.CHANNEL_ERROR, "close of closed channel")
+			}
+			return ch
+		},
+	},
+	"wait": {
+		Name: "wait",
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for wait: want=1, got=%d", len(args))
+			}
+			task, ok := args[0].(*object.Task)
+			if !ok {
+				return newError(object.TYPE_ERROR, "argument to wait must be TASK, got %s", args[0].Type())
+			}
+			<-task.Done // 1. 等待任务结束
+
+			taskMu.Lock() // 2. 任务中的错误已经被观察到，退出时不再报告
+			unobserved = slices.DeleteFunc(unobserved, func(t *object.Task) bool { return t == task })
+			taskMu.Unlock()
+
+			return task.Result // 3. 返回任务结果，任务中的错误在此重新抛出
+		},
+	},
+}
@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpawnAndChannels(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let add = fn(a, b) { a + b }; wait(spawn add(1, 2))`, "3"},
+		{`let f = fn(x = 1) { x }; wait(spawn f(x: 5))`, "5"},
+		{`let c = chan(); let produce = fn() { send(c, 1); send(c, 2) }; spawn produce(); [recv(c), recv(c)]`, "[1, 2]"},
+		{`let c = chan(2); send(c, "a"); send(c, "b"); close(c); [recv(c), recv(c)]`, `["a", "b"]`},
+		{`let c = chan(1); close(c); try { recv(c) } catch (e) { e.kind }`, "ChannelError"},
+		{`let c = chan(1); close(c); try { send(c, 1) } catch (e) { e.message }`, "send on closed channel"},
+		{`let c = chan(1); close(c); try { close(c) } catch (e) { e.message }`, "close of closed channel"},
+		{`let f = fn() { throw "boom" }; let t = spawn f(); try { wait(t) } catch (e) { e.message }`, "boom"},
+		{`let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } }; let ts = [spawn f(100) for i in 0..10]; [wait(t) for t in ts]`, "[0, 0, 0, 0, 0, 0, 0, 0, 0, 0]"},
+		{`chan(-1)`, "ERROR: argument to chan must be a non-negative INTEGER, got -1"},
+		{`wait(1)`, "ERROR: argument to wait must be TASK, got INTEGER"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let c = chan(); select { case recv(c) { 1 } default { 2 } }`, "2"},
+		{`let c = chan(1); send(c, 5); select { case v = recv(c) { v * 2 } default { 0 } }`, "10"},
+		{`let c = chan(1); select { case send(c, 7) { recv(c) } default { 0 } }`, "7"},
+		{`let a = chan(); let b = chan(1); send(b, "b"); select { case v = recv(a) { v } case v = recv(b) { v } }`, "b"},
+		{`let c = chan(); let f = fn() { send(c, 3) }; spawn f(); select { case v = recv(c) { v } }`, "3"},
+		{`let c = chan(1); close(c); try { select { case recv(c) { 1 } } } catch (e) { e.kind }`, "ChannelError"},
+		{`select { case recv(1) { 1 } }`, "ERROR: argument to recv must be CHANNEL, got INTEGER"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestUnobservedTaskErrors(t *testing.T) {
+	unobserved = nil
+	testEval(t, `let f = fn(msg) { throw msg }; let lost = spawn f("lost"); let seen = spawn f("seen"); try { wait(seen) } catch (e) { e }`)
+
+	deadline := time.Now().Add(time.Second)
+	for len(UnobservedTaskErrors()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	errs := UnobservedTaskErrors()
+	if len(errs) != 1 || errs[0].Message != "lost" {
+		t.Errorf("got %v, want only the error of the task nobody waited for", errs)
+	}
+	unobserved = nil
+}
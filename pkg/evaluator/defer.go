@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// evalDeferStatement 评估 defer 语句。与 Go 相同，被调用对象和实参立即求值，调用本身推迟到函数结束时执行
+func evalDeferStatement(node *ast.DeferStatement, env *object.Environment) object.Object {
+	frame := env.Frame()
+	if frame == nil { // 1. defer 只能出现在函数中
+		return newError(object.TYPE_ERROR, "defer outside function")
+	}
+
+	if node.Call.Function.TokenLiteral() == "recover" { // 1.1. defer recover() 直接取走本函数中的错误
+		if len(node.Call.Arguments) != 0 {
+			return newError(object.ARGUMENT_ERROR, "wrong number of arguments for recover: want=0, got=%d", len(node.Call.Arguments))
+		}
+		frame.Defers = append(frame.Defers, func() object.Object {
+			frame.Panic = nil
+			return NULL
+		})
+		return NULL
+	}
+
+	function := Eval(node.Call.Function, env) // 2. 立即评估被调用对象和实参
+	if isAbrupt(function) {
+		return function
+	}
+	args, kwargs, abrupt := evalCallArguments(node.Call.Arguments, env)
+	if abrupt != nil {
+		return abrupt
+	}
+
+	frame.Defers = append(frame.Defers, func() object.Object { // 3. 记录推迟的调用
+		if fn, ok := function.(*object.Function); ok {
+			return callFunction(fn, args, kwargs, frame) // 3.1. 被推迟的函数可以通过 recover 访问该调用帧中的错误
+		}
+		return applyFunction(function, args, kwargs)
+	})
+
+	return NULL // 4. defer 语句本身没有值
+}
+
+// runDeferred 在函数结束时按后进先出的顺序执行 defer 注册的调用，result 是函数体的结果。
+// 函数体出错时，错误在执行 defer 期间保存在调用帧中，可以被 recover 取走；
+// defer 中抛出的错误会替换之前的错误或返回值。所有 defer 执行完后仍未被取走的错误作为函数的结果，
+// 错误被 recover 取走时函数返回 nil
+func runDeferred(frame *object.Frame, result object.Object) object.Object {
+	if len(frame.Defers) == 0 {
+		return result
+	}
+
+	errObj, failed := result.(*object.Error)
+	if failed { // 1. 记录函数体中的错误
+		frame.Panic = errObj
+	}
+
+	for i := len(frame.Defers) - 1; i >= 0; i-- { // 2. 按后进先出的顺序执行
+		if deferred, ok := frame.Defers[i]().(*object.Error); ok {
+			frame.Panic = deferred // 2.1. defer 中的错误替换之前的错误
+			failed = true
+		}
+	}
+	frame.Defers = nil
+
+	switch {
+	case frame.Panic != nil: // 3. 错误没有被取走
+		return frame.Panic
+	case failed: // 4. 错误已经被 recover 取走
+		return NULL
+	default: // 5. 正常结束，返回函数体的结果
+		return result
+	}
+}
+
+// recoverError 评估 recover()，取走正在执行 defer 的函数中的错误并把它作为错误值返回。
+// 与 Go 相同，只有被 defer 直接调用的函数中的 recover 才有效，其他情况以及没有错误时返回 nil
+func recoverError(env *object.Environment) object.Object {
+	frame := env.Frame()
+	if frame == nil || frame.DeferredBy == nil || frame.DeferredBy.Panic == nil {
+		return NULL
+	}
+
+	errObj := frame.DeferredBy.Panic
+	frame.DeferredBy.Panic = nil // 1. 取走错误，函数不再因该错误失败
+	return &object.ErrorValue{Kind: errObj.Kind, Message: errObj.Message, Value: errObj.Value}
+}
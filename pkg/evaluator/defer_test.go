@@ -0,0 +1,21 @@
+package evaluator
+
+import "testing"
+
+func TestRecoverInTailPosition(t *testing.T) {
+	expectInspect(t, `
+let f = fn() { defer fn() { recover() }(); throw "a" };
+f()`, "nil")
+}
+
+func TestRecoverReturnsErrorValue(t *testing.T) {
+	expectInspect(t, `
+let f = fn() { defer fn() { let e = recover(); throw e.message + "!" }(); throw "boom" };
+try { f() } catch (e) { e.message }`, "boom!")
+}
+
+func TestDeferRecover(t *testing.T) {
+	expectInspect(t, `
+let f = fn() { defer recover(); throw "a" };
+f()`, "nil")
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import "testing"
+
+func TestEnumTagAndVariantTest(t *testing.T) {
+	enum := "enum Shape { Circle(r), Rect(w, h), Empty };"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Circle(1).tag", "Circle"},
+		{"Empty.tag", "Empty"},
+		{"Circle(1) is Circle", "true"},
+		{"Rect(1, 2) is Circle", "false"},
+		{"Empty is Empty", "true"},
+		{"Rect(1, 2) is Shape", "true"},
+		{"3 is Shape", "false"},
+		{"let area = fn(s) { if (s is Circle) { s.r * s.r } else { if (s is Rect) { s.w * s.h } else { 0 } } }; [area(Circle(2)), area(Rect(2, 3)), area(Empty)]", "[4, 6, 0]"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, enum+tt.input, tt.want)
+	}
+}
+
+func TestImplWhileTaskCallsMethods(t *testing.T) {
+	input := `
+enum Counter { C(n) };
+impl Counter { fn get(c) { c.n } };
+let sum = fn(i, acc) { if (i == 0) { acc } else { sum(i - 1, acc + C(i).get()) } };
+let task = spawn sum(300, 0);
+impl Counter { fn twice(c) { c.n * 2 }; fn other(c) { 0 } };
+[wait(task), C(4).twice()]`
+	expectInspect(t, input, "[45150, 8]")
+}
+
+func TestMatchExpression(t *testing.T) {
+	enum := "enum Shape { Circle(r), Rect(w, h), Empty }; let area = fn(s) { match s { case Circle(r) { 3 * r * r } case Rect(w, h) { w * h } case Empty { 0 } } }; "
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"[area(Circle(2)), area(Rect(2, 3)), area(Empty)]", "[12, 6, 0]"},
+		{"match Rect(2, 3) { case Rect(_, h) { h } }", "3"},
+		{"match Rect(2, 3) { case Circle { 1 } case Rect { 2 } }", "2"},
+		{"match Empty { case Circle(r) { r } default { \"other\" } }", "other"},
+		{"match 5 { case Circle(r) { r } default { \"not a shape\" } }", "not a shape"},
+		{"let r = 10; match Circle(1) { case Circle(r) { r } }; r", "10"},
+		{"let v = match Circle(4) { case Circle(r) { r + 1 } }; v", "5"},
+		{"match Empty { case Circle(r) { r } }", "ERROR: no case matches Empty"},
+		{"match Circle(1) { case Circle(a, b) { a } }", "ERROR: wrong number of names in pattern Circle: want=1, got=2"},
+		{"match Circle(1) { case area { 1 } }", "ERROR: case area in match must be a VARIANT, got FUNCTION"},
+		{"let count = fn(s, n) { match s { case Circle(r) { if (n == 0) { r } else { count(s, n - 1) } } } }; count(Circle(7), 100000)", "7"},
+		{"let first = fn(s) { match s { case Circle(r) { return r; } default { 0 } }; 99 }; [first(Circle(1)), first(Empty)]", "[1, 99]"},
+		{"match Ok(3) { case Ok(v) { v } case Err(e) { e } }", "3"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, enum+tt.input, tt.want)
+	}
+}
@@ -1,12 +1,21 @@
 package evaluator
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"punyGo/pkg/ast"
 	"punyGo/pkg/object"
 )
 
+// 布尔值和空值在整个解释器中只有唯一的实例，便于直接比较指针
+var (
+	NULL  = &object.Null{}
+	TRUE  = &object.Boolean{Value: true}
+	FALSE = &object.Boolean{Value: false}
+)
+
 // Eval 函数是评估器的入口，根据节点类型调用相应的评估函数
 func Eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
@@ -23,44 +32,735 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
+	// 处理 Boolean 节点，返回对应的布尔对象
+	case *ast.Boolean:
+		return nativeBoolToBooleanObject(node.Value)
+
+	// 处理 NilLiteral 节点，返回空值对象
+	case *ast.NilLiteral:
+		return NULL
+
 	// 处理 PrefixExpression 节点，评估前缀表达式
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env) // 1. 评估前缀表达式右侧的表达式
-		if isError(right) {            // 2. 检查是否评估过程中产生错误
-			return right // 3. 如果有错误，直接返回错误对象
+		if isAbrupt(right) {           // 2. 检查是否评估过程中产生错误或提前返回
+			return right // 3. 如果有，直接向外传递
 		}
 		return evalPrefixExpression(node.Operator, right) // 4. 根据操作符和右侧对象评估前缀表达式
 
+	// 处理 PostfixExpression 节点，评估后缀表达式
+	case *ast.PostfixExpression:
+		left := Eval(node.Left, env) // 1. 评估后缀表达式左侧的表达式
+		if isAbrupt(left) {          // 2. 检查是否评估过程中产生错误或提前返回
+			return left
+		}
+		return evalPostfixExpression(node.Operator, left) // 3. 根据操作符和左侧对象评估后缀表达式
+
 	// 处理 InfixExpression 节点，评估中缀表达式
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env) // 1. 评估中缀表达式左侧的表达式
-		if isError(left) {           // 2. 检查是否评估过程中产生错误
-			return left // 3. 如果有错误，直接返回错误对象
+		if isAbrupt(left) {          // 2. 检查是否评估过程中产生错误或提前返回
+			return left // 3. 如果有，直接向外传递
+		}
+		if node.Operator == "??" && left != NULL { // 3.1. "??" 左侧不为 nil 时不评估右侧
+			return left
 		}
 		right := Eval(node.Right, env) // 4. 评估中缀表达式右侧的表达式
-		if isError(right) {            // 5. 检查是否评估过程中产生错误
-			return right // 6. 如果有错误，直接返回错误对象
+		if isAbrupt(right) {           // 5. 检查是否评估过程中产生错误或提前返回
+			return right // 6. 如果有，直接向外传递
 		}
 		return evalInfixExpression(node.Operator, left, right) // 7. 根据操作符、左侧和右侧对象评估中缀表达式
 
 	// 处理 LetStatement 节点，评估变量声明和赋值
 	case *ast.LetStatement:
 		val := Eval(node.Value, env) // 1. 评估赋值表达式的值
-		if isError(val) {            // 2. 检查是否评估过程中产生错误
-			return val // 3. 如果有错误，直接返回错误对象
+		if isAbrupt(val) {           // 2. 检查是否评估过程中产生错误或提前返回
+			return val // 3. 如果有，直接向外传递
+		}
+		if node.Names != nil { // 3.1. 解构赋值
+			return bindNames(node.Names, val, env)
 		}
 		env.Set(node.Name.Value, val) // 4. 在环境中设置变量名和对应的值
-		return nil                    // 5. 返回 nil
+		return NULL                   // 5. 声明语句没有值
+
+	// 处理 EnumStatement 节点，定义枚举类型及其变体
+	case *ast.EnumStatement:
+		return evalEnumStatement(node, env)
+
+	// 处理 ImplStatement 节点，为枚举定义方法
+	case *ast.ImplStatement:
+		return evalImplStatement(node, env)
+
+	// 处理 BlockStatement 节点，依次评估语句块中的语句
+	case *ast.BlockStatement:
+		return evalBlockStatement(node, env)
+
+	// 处理 ThrowStatement 节点，抛出错误
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env) // 1. 评估被抛出的表达式
+		if isAbrupt(val) {           // 2. 如果评估本身出错或提前返回，直接向外传递
+			return val
+		}
+		return throwValue(val) // 3. 将值包装为错误对象并抛出
+
+	// 处理 DeferStatement 节点，推迟调用直到函数结束
+	case *ast.DeferStatement:
+		return evalDeferStatement(node, env)
+
+	// 处理 TryStatement 节点，捕获 try 语句块中的错误
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+
+	// 处理 ReturnStatement 节点，将返回值包装为返回值对象
+	case *ast.ReturnStatement:
+		val := Eval(node.ReturnValue, env) // 1. 评估返回值表达式
+		if isAbrupt(val) {                 // 2. 检查是否评估过程中产生错误或提前返回
+			return val
+		}
+		return &object.ReturnValue{Value: val} // 3. 包装为返回值对象，由外层函数调用展开
+
+	// 处理 YieldStatement 节点，从生成器中产出一个值
+	case *ast.YieldStatement:
+		return evalYieldStatement(node, env)
+
+	// 处理 FunctionLiteral 节点，创建捕获当前环境的函数对象
+	case *ast.FunctionLiteral:
+		return &object.Function{
+			Name:        node.Name,
+			IsGenerator: node.IsGenerator,
+			Parameters:  node.Parameters,
+			Defaults:    node.Defaults,
+			Rest:        node.Rest,
+			Body:        node.Body,
+			Env:         env,
+		}
+
+	// 处理 FunctionStatement 节点，创建函数对象并绑定到函数名
+	case *ast.FunctionStatement:
+		fn := Eval(node.Function, env)  // 1. 创建函数对象
+		env.Set(node.Function.Name, fn) // 2. 在环境中绑定函数名
+		return NULL                     // 3. 声明语句没有值
+
+	// 处理 SpawnExpression 节点，在新任务中执行调用
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(node, env)
+
+	// 处理 SelectStatement 节点，等待多个通信操作之一
+	case *ast.SelectStatement:
+		return evalSelectStatement(node, env)
+
+	// 处理 ImportStatement 节点，导入模块
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+
+	// 处理 ExportStatement 节点，导出模块顶层的声明
+	case *ast.ExportStatement:
+		return evalExportStatement(node, env)
+
+	// 处理 StringLiteral 节点，返回对应的字符串对象
+	case *ast.StringLiteral:
+		return &object.String{Value: node.Value}
+
+	// 处理 BytesLiteral 节点，创建字节串对象
+	case *ast.BytesLiteral:
+		return &object.Bytes{Value: append([]byte{}, node.Value...)}
+
+	// 处理 CharLiteral 节点，创建字符对象
+	case *ast.CharLiteral:
+		return &object.Char{Value: node.Value}
+
+	// 处理 RegexLiteral 节点，编译正则表达式
+	case *ast.RegexLiteral:
+		return evalRegexLiteral(node)
+
+	// 处理 InterpolatedString 节点，依次评估插值表达式并拼接
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+
+	// 处理 MemberExpression 节点，访问对象的成员
+	case *ast.MemberExpression:
+		obj := Eval(node.Object, env) // 1. 评估被访问的对象
+		if isAbrupt(obj) {
+			return obj
+		}
+		if node.Optional && obj == NULL { // 2. "?." 访问 nil 的成员时结果为 nil
+			return NULL
+		}
+		return evalMemberExpression(obj, node.Property.Value) // 3. 根据对象类型访问成员
+
+	// 处理 IndexExpression 节点，按下标访问对象
+	case *ast.IndexExpression:
+		left := Eval(node.Left, env) // 1. 评估被访问的对象
+		if isAbrupt(left) {
+			return left
+		}
+		if node.Optional && left == NULL { // 2. "?[" 访问 nil 时结果为 nil，且不评估下标
+			return NULL
+		}
+		index := Eval(node.Index, env) // 3. 评估下标
+		if isAbrupt(index) {
+			return index
+		}
+		return evalIndexExpression(left, index) // 4. 根据对象类型访问下标
+
+	// 处理 SliceExpression 节点，截取对象的一部分
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+
+	// 处理 ArrayLiteral 节点，依次评估元素并创建数组对象
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env) // 1. 依次评估元素
+		if len(elements) == 1 && isAbrupt(elements[0]) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements} // 2. 创建数组对象
+
+	// 处理 SetLiteral 节点，创建集合对象
+	case *ast.SetLiteral:
+		elements := evalExpressions(node.Elements, env) // 1. 依次评估元素
+		if len(elements) == 1 && isAbrupt(elements[0]) {
+			return elements[0]
+		}
+		return newSet(elements) // 2. 创建集合对象，重复的元素只保留一个
+
+	// 处理 ListComprehension 节点，由推导式创建数组
+	case *ast.ListComprehension:
+		return evalListComprehension(node, env)
+
+	// 处理 HashComprehension 节点，由推导式创建哈希
+	case *ast.HashComprehension:
+		return evalHashComprehension(node, env)
+
+	// 处理 TupleLiteral 节点，创建元组对象
+	case *ast.TupleLiteral:
+		elements := evalExpressions(node.Elements, env) // 1. 依次评估元素
+		if len(elements) == 1 && isAbrupt(elements[0]) {
+			return elements[0]
+		}
+		return &object.Tuple{Elements: elements} // 2. 创建元组对象
+
+	// 处理 HashLiteral 节点，依次评估各项并创建哈希对象
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+
+	// 处理 SpreadExpression 节点，展开只能出现在实参、数组和哈希字面量中
+	case *ast.SpreadExpression:
+		return newError(object.SYNTAX_ERROR, "spread %s is only allowed in calls, array and hash literals", node.String())
+
+	// 处理 RangeExpression 节点，创建区间对象
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+
+	// 处理 IfExpression 节点，根据条件选择执行的语句块
+	case *ast.IfExpression:
+		branch, cond := ifBranch(node, env) // 1. 评估条件并选择分支
+		if cond != nil {
+			return cond
+		}
+		if branch == nil { // 2. 没有被选中的分支时结果为 nil
+			return NULL
+		}
+		return Eval(branch, env) // 3. 执行被选中的分支
+
+	// 处理 MatchExpression 节点，按枚举值的变体选择分支
+	case *ast.MatchExpression:
+		branch, branchEnv, err := matchBranch(node, env) // 1. 评估被匹配的值并选择分支
+		if err != nil {
+			return err
+		}
+		return Eval(branch, branchEnv) // 2. 在绑定了字段的环境中执行被选中的分支
+
+	// 处理 ForStatement 节点，遍历对象中的元素
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
 
 	// 处理 Identifier 节点，查找变量的值
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 
+	// 处理 CallExpression 节点，评估被调用对象和实参后执行调用
+	case *ast.CallExpression:
+		if node.Function.TokenLiteral() == "quote" { // 0. quote 不对实参求值，而是返回其 AST
+			if len(node.Arguments) != 1 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for quote: want=1, got=%d", len(node.Arguments))
+			}
+			return quote(node.Arguments[0], env)
+		}
+		if node.Function.TokenLiteral() == "recover" { // 0.1. recover 需要访问调用它的函数的调用帧
+			if len(node.Arguments) != 0 {
+				return newError(object.ARGUMENT_ERROR, "wrong number of arguments for recover: want=0, got=%d", len(node.Arguments))
+			}
+			return recoverError(env)
+		}
+		function := Eval(node.Function, env) // 1. 评估被调用的表达式
+		if isAbrupt(function) {              // 2. 检查是否评估过程中产生错误或提前返回
+			return function
+		}
+		args, kwargs, abrupt := evalCallArguments(node.Arguments, env) // 3. 依次评估位置实参和关键字实参
+		if abrupt != nil {                                             // 4. 如果实参评估出错或提前返回，直接向外传递
+			return abrupt
+		}
+		return applyFunction(function, args, kwargs) // 5. 执行调用
+
 	// 其他未处理的节点类型
 	default:
-		return nil
+		return NULL
+	}
+}
+
+// evalEnumStatement 评估枚举声明，将枚举类型和各个变体绑定到环境中
+func evalEnumStatement(node *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := &object.Enum{Name: node.Name.Value} // 1. 创建枚举类型对象
+
+	for _, v := range node.Variants { // 2. 依次创建每个变体
+		variant := &object.Variant{Enum: enum, Name: v.Name.Value}
+		for _, f := range v.Fields {
+			variant.Fields = append(variant.Fields, f.Value) // 2.1. 记录变体的字段名
+		}
+		enum.Variants = append(enum.Variants, variant)
+
+		if len(variant.Fields) == 0 { // 2.2. 无字段的变体直接绑定为枚举值
+			env.Set(variant.Name, &object.EnumValue{Variant: variant})
+		} else { // 2.3. 带字段的变体绑定为构造函数
+			env.Set(variant.Name, variant)
+		}
+	}
+
+	env.Set(enum.Name, enum) // 3. 绑定枚举类型本身
+	return NULL              // 4. 声明语句没有值
+}
+
+// evalIsExpression 评估变体测试 "x is V"：V 为枚举类型时判断 x 是否属于该枚举，
+// 为变体构造函数或无字段的枚举值时判断 x 的标签是否为该变体
+func evalIsExpression(left, right object.Object) object.Object {
+	ev, ok := left.(*object.EnumValue)
+	switch right := right.(type) {
+	case *object.Enum:
+		return nativeBoolToBooleanObject(ok && ev.Variant.Enum == right) // 1. 非枚举值不属于任何枚举
+	case *object.Variant:
+		return nativeBoolToBooleanObject(ok && ev.Variant == right) // 2. 比较标签，不比较负载
+	case *object.EnumValue:
+		if len(right.Variant.Fields) == 0 {
+			return nativeBoolToBooleanObject(ok && ev.Variant == right.Variant) // 3. 无字段的变体以枚举值的形式绑定
+		}
+	}
+	return newError(object.TYPE_ERROR, "right operand of is must be ENUM or VARIANT, got %s", right.Type())
+}
+
+// evalImplStatement 评估 impl 语句，将方法加入枚举的方法表，同名方法会被替换
+func evalImplStatement(node *ast.ImplStatement, env *object.Environment) object.Object {
+	val := evalIdentifier(node.Name, env) // 1. 查找被实现的枚举
+	if isAbrupt(val) {
+		return val
+	}
+	enum, ok := val.(*object.Enum)
+	if !ok {
+		return newError(object.TYPE_ERROR, "impl target must be ENUM, got %s", val.Type())
+	}
+
+	for _, m := range node.Methods { // 2. 方法捕获 impl 语句所在的环境
+		enum.SetMethod(m.Name, Eval(m, env).(*object.Function))
+	}
+
+	return NULL // 3. 声明语句没有值
+}
+
+// evalBlockStatement 评估语句块，遇到返回值或错误时立即停止，但不展开返回值
+func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	var result object.Object = NULL // 空语句块的值为 nil
+
+	for _, statement := range block.Statements { // 1. 遍历所有语句
+		result = Eval(statement, env) // 2. 评估当前语句
+		if isAbrupt(result) {         // 3. 返回值和错误交给外层处理
+			return result
+		}
+	}
+
+	return result // 4. 返回最后一个评估的对象
+}
+
+// throwValue 将被抛出的值转换为错误对象，被捕获过的错误值会按原样重新抛出
+func throwValue(val object.Object) *object.Error {
+	if ev, ok := val.(*object.ErrorValue); ok { // 1. 重新抛出被捕获的错误，保留种类和信息
+		return &object.Error{Kind: ev.Kind, Message: ev.Message, Value: ev.Value}
+	}
+	if val == NULL { // 2. nil 不能作为错误抛出
+		return newError(object.TYPE_ERROR, "cannot throw nil")
+	}
+	return &object.Error{Kind: object.THROWN_ERROR, Message: val.Inspect(), Value: val} // 3. 普通值包装为错误
+}
+
+// evalTryStatement 评估 try 语句，catch 捕获错误，finally 总会执行且其中的错误会覆盖之前的结果
+func evalTryStatement(node *ast.TryStatement, env *object.Environment) object.Object {
+	result := Eval(node.Block, env) // 1. 评估 try 语句块
+
+	if errObj, ok := result.(*object.Error); ok && node.Catch != nil { // 2. 如果出错且存在 catch，执行 catch 语句块
+		catchEnv := object.NewEnvironment(env) // 2.1. catch 参数只在 catch 语句块内可见
+		if node.CatchParam != nil {
+			catchEnv.Set(node.CatchParam.Value, &object.ErrorValue{
+				Kind:    errObj.Kind,
+				Message: errObj.Message,
+				Value:   errObj.Value,
+			})
+		}
+		result = Eval(node.Catch, catchEnv) // 2.2. catch 语句块的结果作为 try 语句的结果
+	}
+
+	if node.Finally != nil { // 3. 无论是否出错都执行 finally 语句块
+		finally := Eval(node.Finally, env)
+		if isAbrupt(finally) { // 3.1. finally 中的返回或错误覆盖之前的结果
+			return finally
+		}
+	}
+
+	return result // 4. 返回 try 或 catch 的结果
+}
+
+// evalExpressions 依次评估表达式列表，展开表达式会被替换为其中的所有元素；遇到错误或提前返回时返回只包含该对象的列表
+func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
+	var result []object.Object
+
+	for _, e := range exps { // 1. 遍历所有表达式
+		if spread, ok := e.(*ast.SpreadExpression); ok { // 2. 展开可遍历的对象
+			val := Eval(spread.Value, env)
+			if isAbrupt(val) {
+				return []object.Object{val}
+			}
+			abrupt := iterate(val, func(item object.Object) object.Object {
+				result = append(result, item)
+				return nil
+			})
+			if abrupt != nil {
+				return []object.Object{abrupt}
+			}
+			continue
+		}
+
+		evaluated := Eval(e, env) // 3. 评估当前表达式
+		if isAbrupt(evaluated) {  // 4. 如果出错或提前返回，只返回该对象
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated) // 5. 收集评估结果
+	}
+
+	return result // 6. 返回评估结果列表
+}
+
+// keywordArg 是一个已经求值的关键字实参
+type keywordArg struct {
+	name  string        // 形参名
+	value object.Object // 实参的值
+}
+
+// evalCallArguments 评估调用的实参，返回位置实参和按书写顺序排列的关键字实参；出错或提前返回时第三个返回值为该对象
+func evalCallArguments(exps []ast.Expression, env *object.Environment) ([]object.Object, []keywordArg, object.Object) {
+	n := len(exps) // 1. 解析器保证关键字实参都位于位置实参之后
+	for n > 0 {
+		if _, ok := exps[n-1].(*ast.KeywordArgument); !ok {
+			break
+		}
+		n--
+	}
+
+	args := evalExpressions(exps[:n], env) // 2. 评估位置实参
+	if len(args) == 1 && isAbrupt(args[0]) {
+		return nil, nil, args[0]
+	}
+
+	var kwargs []keywordArg
+	for _, e := range exps[n:] { // 3. 评估关键字实参
+		kw := e.(*ast.KeywordArgument)
+		val := Eval(kw.Value, env)
+		if isAbrupt(val) {
+			return nil, nil, val
+		}
+		kwargs = append(kwargs, keywordArg{name: kw.Name.Value, value: val})
+	}
+
+	return args, kwargs, nil
+}
+
+// applyFunction 根据被调用对象的类型执行调用，只有用户定义的函数接受关键字实参
+func applyFunction(fn object.Object, args []object.Object, kwargs []keywordArg) object.Object {
+	if _, ok := fn.(*object.Function); !ok && len(kwargs) > 0 { // 0. 其他可调用对象没有形参名
+		return newError(object.ARGUMENT_ERROR, "%s does not accept keyword arguments", fn.Inspect())
+	}
+
+	switch fn := fn.(type) {
+	case *object.Variant:
+		if len(args) != len(fn.Fields) { // 1. 检查实参数量是否与变体字段数量一致
+			return newError(object.ARGUMENT_ERROR, "wrong number of arguments for %s: want=%d, got=%d", fn.Name, len(fn.Fields), len(args))
+		}
+		return &object.EnumValue{Variant: fn, Values: args} // 2. 构造枚举值
+	case *object.Function:
+		return callFunction(fn, args, kwargs, nil) // 3. 调用用户定义的函数
+	case *object.Builtin:
+		return fn.Fn(args...) // 4. 调用内置函数
+	default:
+		return newError(object.TYPE_ERROR, "not a function: %s", fn.Type()) // 5. 不可调用的对象，返回错误对象
 	}
-	return nil
+}
+
+// callFunction 调用用户定义的函数，deferredBy 是由 defer 发起调用时注册它的函数的调用帧，其他情况为 nil
+func callFunction(fn *object.Function, args []object.Object, kwargs []keywordArg, deferredBy *object.Frame) object.Object {
+	for {
+		frame := &object.Frame{DeferredBy: deferredBy}
+		env, errObj := extendFunctionEnv(fn, args, kwargs, frame) // 1. 创建函数调用环境并绑定实参
+		if errObj != nil {
+			return errObj
+		}
+		if fn.IsGenerator { // 2. 生成器函数不会立即执行，而是返回生成器
+			return newGenerator(fn, env, frame)
+		}
+
+		evaluated := evalTail(fn.Body, env) // 3. 执行函数体，尾位置上的调用不会立即执行
+		if call, ok := evaluated.(*tailCall); ok {
+			if len(frame.Defers) == 0 { // 3.1. 在同一层循环中执行尾调用，Go 栈不会增长
+				fn, args, kwargs, deferredBy = call.fn, call.args, call.kwargs, nil
+				continue
+			}
+			evaluated = callFunction(call.fn, call.args, call.kwargs, nil) // 3.2. 有 defer 时必须先完成调用再执行 defer
+		}
+
+		evaluated = runDeferred(frame, evaluated) // 4. 执行 defer 注册的调用
+		return unwrapReturnValue(evaluated)       // 5. 展开返回值
+	}
+}
+
+// extendFunctionEnv 创建嵌套在闭包环境中的函数调用环境，并将实参绑定到形参。
+// 位置实参按顺序绑定，关键字实参按名字绑定，仍未绑定的形参使用默认值；
+// 默认值在每次调用时于函数调用环境中求值，因此可以引用闭包中的变量和之前的形参
+func extendFunctionEnv(fn *object.Function, args []object.Object, kwargs []keywordArg, frame *object.Frame) (*object.Environment, object.Object) {
+	got := len(args) + len(kwargs)
+	if got < requiredParameters(fn) || (fn.Rest == nil && got > len(fn.Parameters)) { // 1. 检查实参数量
+		return nil, arityError(fn, got)
+	}
+
+	env := object.NewFrameEnvironment(fn.Env, frame) // 2. 创建关联调用帧的新环境
+	bound := map[string]bool{}
+
+	for i, param := range fn.Parameters { // 3. 依次绑定位置实参
+		if i < len(args) {
+			env.Set(param.Value, args[i])
+			bound[param.Value] = true
+		}
+	}
+	if fn.Rest != nil { // 4. 多余的实参收集到可变参数的数组中，该数组不与调用方共享存储
+		rest := []object.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+		env.Set(fn.Rest.Value, &object.Array{Elements: rest})
+	}
+
+	for _, kw := range kwargs { // 5. 按名字绑定关键字实参
+		if !hasParameter(fn, kw.name) {
+			return nil, newError(object.ARGUMENT_ERROR, "%s got an unexpected keyword argument %s", functionName(fn), kw.name)
+		}
+		if bound[kw.name] {
+			return nil, newError(object.ARGUMENT_ERROR, "%s got multiple values for argument %s", functionName(fn), kw.name)
+		}
+		env.Set(kw.name, kw.value)
+		bound[kw.name] = true
+	}
+
+	for i, param := range fn.Parameters { // 6. 未绑定的形参使用默认值
+		if bound[param.Value] {
+			continue
+		}
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			return nil, newError(object.ARGUMENT_ERROR, "%s missing argument %s", functionName(fn), param.Value)
+		}
+		val := Eval(fn.Defaults[i], env)
+		if isAbrupt(val) {
+			return nil, val
+		}
+		env.Set(param.Value, val)
+	}
+
+	return env, nil // 7. 返回函数调用环境
+}
+
+// requiredParameters 返回函数中没有默认值的形参个数
+func requiredParameters(fn *object.Function) int {
+	required := 0
+	for i := range fn.Parameters {
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			required++
+		}
+	}
+	return required
+}
+
+// hasParameter 判断函数是否有名为 name 的普通形参，可变参数不能通过关键字传入
+func hasParameter(fn *object.Function, name string) bool {
+	for _, param := range fn.Parameters {
+		if param.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// arityError 返回实参数量错误，错误信息中包含期望的数量和实际的数量
+func arityError(fn *object.Function, got int) *object.Error {
+	required := requiredParameters(fn)
+	var want string
+	switch {
+	case fn.Rest != nil:
+		want = fmt.Sprintf(">=%d", required) // 1. 可变参数函数只有下限
+	case required == len(fn.Parameters):
+		want = fmt.Sprintf("=%d", required) // 2. 没有默认值时数量固定
+	default:
+		want = fmt.Sprintf("=%d..%d", required, len(fn.Parameters)) // 3. 有默认值时数量是一个范围
+	}
+	return newError(object.ARGUMENT_ERROR, "wrong number of arguments for %s: want%s, got=%d", functionName(fn), want, got)
+}
+
+// ifBranch 评估条件表达式的条件，返回被选中的语句块；条件评估出错或提前返回时第二个返回值为该对象
+func ifBranch(node *ast.IfExpression, env *object.Environment) (*ast.BlockStatement, object.Object) {
+	cond := Eval(node.Condition, env)
+	if isAbrupt(cond) {
+		return nil, cond
+	}
+	if isTruthy(cond) {
+		return node.Consequence, nil // 1. 条件成立
+	}
+	return node.Alternative, nil // 2. 条件不成立，没有 else 时为 nil
+}
+
+// matchBranch 评估被匹配的值，按顺序选择第一个变体相同的分支，返回分支的语句块和绑定了字段的环境。
+// 没有分支匹配时选择 default 分支，没有 default 分支时返回 MatchError
+func matchBranch(node *ast.MatchExpression, env *object.Environment) (*ast.BlockStatement, *object.Environment, object.Object) {
+	subject := Eval(node.Subject, env)
+	if isAbrupt(subject) {
+		return nil, nil, subject
+	}
+	ev, _ := subject.(*object.EnumValue) // 非枚举值不匹配任何变体
+
+	for _, mc := range node.Cases {
+		pattern := evalIdentifier(mc.Variant, env) // 1. 分支中的名字必须是变体
+		if isAbrupt(pattern) {
+			return nil, nil, pattern
+		}
+		var variant *object.Variant
+		switch pattern := pattern.(type) {
+		case *object.Variant:
+			variant = pattern
+		case *object.EnumValue: // 无字段的变体以枚举值的形式绑定
+			variant = pattern.Variant
+		default:
+			return nil, nil, newError(object.TYPE_ERROR, "case %s in match must be a VARIANT, got %s", mc.Variant.Value, pattern.Type())
+		}
+		if mc.Names != nil && len(mc.Names) != len(variant.Fields) { // 2. 绑定的变量名与字段一一对应
+			return nil, nil, newError(object.ARGUMENT_ERROR, "wrong number of names in pattern %s: want=%d, got=%d", variant.Name, len(variant.Fields), len(mc.Names))
+		}
+
+		if ev == nil || ev.Variant != variant { // 3. 比较标签，不比较负载
+			continue
+		}
+		caseEnv := object.NewEnvironment(env) // 4. 字段只在分支语句块内可见
+		for i, name := range mc.Names {
+			if name.Value != "_" {
+				caseEnv.Set(name.Value, ev.Values[i])
+			}
+		}
+		return mc.Body, caseEnv, nil
+	}
+
+	if node.Default != nil { // 5. 没有分支匹配时执行 default 分支
+		return node.Default, object.NewEnvironment(env), nil
+	}
+	return nil, nil, newError(object.MATCH_ERROR, "no case matches %s", subject.Inspect())
+}
+
+// isTruthy 判断对象作为条件时是否成立，只有 false 和 nil 不成立
+func isTruthy(obj object.Object) bool {
+	return obj != FALSE && obj != NULL
+}
+
+// unwrapReturnValue 展开返回值对象，使 return 只从当前函数返回
+func unwrapReturnValue(obj object.Object) object.Object {
+	if returnValue, ok := obj.(*object.ReturnValue); ok {
+		return returnValue.Value // 1. 返回值对象展开为其中的值
+	}
+	return obj // 2. 其他对象原样返回
+}
+
+// functionName 返回用于错误信息的函数名
+func functionName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "anonymous function" // 1. 匿名函数没有名字
+	}
+	return fn.Name // 2. 返回函数名
+}
+
+// evalInterpolatedString 评估插值字符串，字符串直接拼接，其他对象使用其字符串表示
+func evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts { // 1. 依次评估每个片段
+		val := Eval(part, env)
+		if isAbrupt(val) {
+			return val
+		}
+
+		if str, ok := val.(*object.String); ok {
+			out.WriteString(str.Value) // 2. 字符串直接拼接
+		} else if ch, ok := val.(*object.Char); ok {
+			out.WriteRune(ch.Value) // 2.1. 字符直接拼接，与字符串相同
+		} else {
+			out.WriteString(val.Inspect()) // 3. 其他对象使用其字符串表示
+		}
+	}
+
+	return &object.String{Value: out.String()} // 4. 返回拼接后的字符串
+}
+
+// evalMemberExpression 根据对象类型访问其成员
+func evalMemberExpression(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.Module:
+		return evalModuleMember(obj, name) // 1. 访问模块导出的成员
+	case *object.EnumValue:
+		for i, field := range obj.Variant.Fields { // 2. 按字段名访问枚举值的负载
+			if field == name {
+				return obj.Values[i]
+			}
+		}
+		if method, ok := obj.Variant.Enum.Method(name); ok { // 2.1. 访问方法得到绑定了接收者的函数
+			return bindMethod(obj, method)
+		}
+		if name == "tag" { // 2.2. 没有同名字段和方法时，tag 是变体名称
+			return &object.String{Value: obj.Variant.Name}
+		}
+	case *object.Set:
+		if method := setMethod(obj, name); method != nil { // 2.3. 集合运算的方法形式
+			return method
+		}
+	case *object.Regex:
+		if member := regexMember(obj, name); member != nil { // 2.4. 正则表达式的属性和方法
+			return member
+		}
+	case *object.Hash:
+		if val, ok := obj.Get(&object.String{Value: name}); ok { // 2.5. 哈希按同名的字符串键取值，与 h["name"] 相同，不存在的键得到 nil
+			return val
+		}
+		return NULL
+	case *object.ErrorValue:
+		switch name { // 3. 访问被捕获错误的种类、信息和被抛出的值
+		case "kind":
+			return &object.String{Value: obj.Kind}
+		case "message":
+			return &object.String{Value: obj.Message}
+		case "value":
+			if obj.Value == nil {
+				return NULL
+			}
+			return obj.Value
+		}
+	}
+	return newError(object.TYPE_ERROR, "%s has no member %s", obj.Type(), name) // 4. 其他成员不存在
 }
 
 // evalIdentifier 评估标识符节点，查找变量的值
@@ -68,12 +768,15 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	if val, ok := env.Get(node.Value); ok { // 1. 在环境中查找标识符对应的值
 		return val // 2. 如果找到，返回对应的对象
 	}
-	return newError("identifier not found: " + node.Value) // 3. 如果未找到，返回错误对象
+	if builtin, ok := builtins[node.Value]; ok { // 3. 在内置对象中查找
+		return builtin
+	}
+	return newError(object.NAME_ERROR, "identifier not found: %s", node.Value) // 4. 如果未找到，返回错误对象
 }
 
 // evalProgram 评估程序节点，依次评估所有语句
 func evalProgram(stmts []ast.Statement, env *object.Environment) object.Object {
-	var result object.Object
+	var result object.Object = NULL // 空程序的值为 nil
 
 	for _, statement := range stmts { // 1. 遍历所有语句
 		result = Eval(statement, env)                            // 2. 评估当前语句
@@ -96,20 +799,49 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 	case "-":
 		return evalMinusPrefixOperatorExpression(right) // 2. 处理 '-' 操作符
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type()) // 3. 未知操作符，返回错误对象
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s%s", operator, right.Type()) // 3. 未知操作符，返回错误对象
+	}
+}
+
+// evalPostfixExpression 评估后缀表达式，根据操作符调用相应的函数
+func evalPostfixExpression(operator string, left object.Object) object.Object {
+	switch operator {
+	case "?":
+		return evalPropagateOperatorExpression(left) // 1. 处理 '?' 操作符
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s%s", left.Type(), operator) // 2. 未知操作符，返回错误对象
+	}
+}
+
+// evalPropagateOperatorExpression 评估 '?' 操作符，Ok 展开为其中的值，Err 作为返回值提前返回
+func evalPropagateOperatorExpression(left object.Object) object.Object {
+	if result, ok := left.(*object.EnumValue); ok {
+		switch result.Variant {
+		case OK:
+			return result.Values[0] // 1. Ok(v) 展开为 v
+		case ERR:
+			return &object.ReturnValue{Value: result} // 2. Err(e) 原样作为返回值提前返回
+		}
 	}
+	return newError(object.TYPE_ERROR, "operator ? expects Ok or Err, got %s", left.Inspect()) // 3. 非 Result 值，返回错误对象
 }
 
-// evalBangOperatorExpression 评估 '!' 操作符，目前仅支持整数类型
+// evalBangOperatorExpression 评估 '!' 操作符，目前仅支持布尔类型
 func evalBangOperatorExpression(right object.Object) object.Object {
-	// 暂不处理布尔值，返回错误
-	return newError("unknown operator: !%s", right.Type()) // 1. 返回未知操作符错误
+	switch right {
+	case TRUE:
+		return FALSE // 1. !true 为 false
+	case FALSE:
+		return TRUE // 2. !false 为 true
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: !%s", right.Type()) // 3. 其他类型返回未知操作符错误
+	}
 }
 
 // evalMinusPrefixOperatorExpression 评估 '-' 操作符，对整数取反
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	if right.Type() != object.INTEGER_OBJ { // 1. 检查右侧对象是否为整数类型
-		return newError("unknown operator: -%s", right.Type()) // 2. 如果不是，返回错误对象
+		return newError(object.OPERATOR_ERROR, "unknown operator: -%s", right.Type()) // 2. 如果不是，返回错误对象
 	}
 
 	value := right.(*object.Integer).Value // 3. 获取整数值
@@ -118,11 +850,42 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 
 // evalInfixExpression 评估中缀表达式，根据操作符和操作数类型调用相应的函数
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
+	if result, ok := evalOperatorMethod(operator, left, right); ok { // 用户类型通过特殊方法重载操作符，优先于内置行为
+		return result
+	}
+
 	switch {
+	case operator == "??":
+		if left != NULL {
+			return left // 0. 左侧不为 nil 时取左侧的值
+		}
+		return right // 0.1. 否则取右侧的值
+	case operator == "in":
+		return evalInExpression(left, right) // 0.2. 成员测试由右侧对象决定
+	case operator == "=~":
+		return evalMatchExpression(left, right) // 0.3. 正则匹配由右侧的正则表达式决定
+	case operator == "is":
+		return evalIsExpression(left, right) // 0.4. 变体测试由右侧的枚举、变体或无字段的枚举值决定
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right) // 1. 如果左右都是整数，调用整数中缀表达式评估
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right) // 2. 如果左右都是字符串，调用字符串中缀表达式评估
+	case left.Type() == object.BYTES_OBJ && right.Type() == object.BYTES_OBJ:
+		return evalBytesInfixExpression(operator, left, right) // 2.0.1. 字节串拼接和比较
+	case left.Type() == object.CHAR_OBJ && right.Type() == object.CHAR_OBJ:
+		return evalCharInfixExpression(operator, left, right) // 2.0.2. 字符按码点比较
+	case left.Type() == object.SET_OBJ && right.Type() == object.SET_OBJ:
+		return evalSetInfixExpression(operator, left.(*object.Set), right.(*object.Set)) // 2.0.3. 集合运算
+	case (left == NULL || right == NULL) && (operator == "==" || operator == "!="):
+		return nativeBoolToBooleanObject((left == right) == (operator == "==")) // 2.1. 任何值都可以与 nil 比较
+	case left.Type() != right.Type():
+		return newError(object.TYPE_ERROR, "type mismatch: %s %s %s", left.Type(), operator, right.Type()) // 3. 类型不匹配，返回错误对象
+	case operator == "==":
+		return nativeBoolToBooleanObject(objectsEqual(left, right)) // 4. 同类型对象比较是否相等
+	case operator == "!=":
+		return nativeBoolToBooleanObject(!objectsEqual(left, right)) // 5. 同类型对象比较是否不等
 	default:
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type()) // 2. 类型不匹配，返回错误对象
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 6. 未知操作符，返回错误对象
 	}
 }
 
@@ -139,21 +902,158 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal} // 5. 执行乘法
 	case "/":
+		if rightVal == 0 {
+			return newError(object.OPERATOR_ERROR, "integer division by zero")
+		}
 		return &object.Integer{Value: leftVal / rightVal} // 6. 执行除法
+	case "%":
+		if rightVal == 0 {
+			return newError(object.OPERATOR_ERROR, "integer modulo by zero")
+		}
+		return &object.Integer{Value: leftVal % rightVal} // 6.0. 执行取余
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal} // 6.1. 执行按位或
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal} // 6.2. 执行按位与
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal} // 6.3. 执行按位异或
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal) // 7. 执行小于比较
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal) // 8. 执行大于比较
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal) // 9. 执行等于比较
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal) // 10. 执行不等于比较
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 11. 未知操作符，返回错误对象
+	}
+}
+
+// evalStringInfixExpression 评估字符串类型的中缀表达式，支持拼接和比较
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.String).Value   // 1. 获取左侧字符串
+	rightVal := right.(*object.String).Value // 2. 获取右侧字符串
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal} // 3. 执行拼接
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal) // 4. 执行等于比较
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal) // 5. 执行不等于比较
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 6. 未知操作符，返回错误对象
+	}
+}
+
+// evalBytesInfixExpression 评估字节串的中缀表达式，支持拼接和比较
+func evalBytesInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Bytes).Value   // 1. 获取左侧字节串
+	rightVal := right.(*object.Bytes).Value // 2. 获取右侧字节串
+
+	switch operator {
+	case "+":
+		return &object.Bytes{Value: append(append([]byte{}, leftVal...), rightVal...)} // 3. 拼接为新的字节串
+	case "==":
+		return nativeBoolToBooleanObject(bytes.Equal(leftVal, rightVal)) // 4. 执行等于比较
+	case "!=":
+		return nativeBoolToBooleanObject(!bytes.Equal(leftVal, rightVal)) // 5. 执行不等于比较
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 6. 未知操作符，返回错误对象
+	}
+}
+
+// evalCharInfixExpression 评估字符的中缀表达式，按码点比较
+func evalCharInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Char).Value   // 1. 获取左侧字符
+	rightVal := right.(*object.Char).Value // 2. 获取右侧字符
+
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal) // 3. 执行小于比较
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal) // 4. 执行大于比较
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal) // 5. 执行等于比较
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal) // 6. 执行不等于比较
+	default:
+		return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 7. 未知操作符，返回错误对象
+	}
+}
+
+// objectsEqual 判断两个对象是否相等，枚举值比较标签和负载
+func objectsEqual(left, right object.Object) bool {
+	switch left := left.(type) {
+	case *object.Integer:
+		r, ok := right.(*object.Integer)
+		return ok && left.Value == r.Value // 1. 整数比较数值
+	case *object.String:
+		r, ok := right.(*object.String)
+		return ok && left.Value == r.Value // 1.1. 字符串比较内容
+	case *object.Bytes:
+		r, ok := right.(*object.Bytes)
+		return ok && bytes.Equal(left.Value, r.Value) // 1.1.1. 字节串比较内容
+	case *object.Char:
+		r, ok := right.(*object.Char)
+		return ok && left.Value == r.Value // 1.1.2. 字符比较码点
+	case *object.Set:
+		r, ok := right.(*object.Set)
+		if !ok || len(left.Keys) != len(r.Keys) { // 1.3. 集合元素个数相同且互相包含时相等
+			return false
+		}
+		for _, k := range left.Keys {
+			if _, found := r.Members[k]; !found {
+				return false
+			}
+		}
+		return true
+	case *object.Tuple:
+		r, ok := right.(*object.Tuple)
+		if !ok || len(left.Elements) != len(r.Elements) { // 1.2. 元组逐个比较元素
+			return false
+		}
+		for i := range left.Elements {
+			if !objectsEqual(left.Elements[i], r.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.EnumValue:
+		r, ok := right.(*object.EnumValue)
+		if !ok || left.Variant != r.Variant { // 2. 枚举值先比较标签
+			return false
+		}
+		for i := range left.Values { // 3. 再逐个比较负载
+			if !objectsEqual(left.Values[i], r.Values[i]) {
+				return false
+			}
+		}
+		return true
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type()) // 7. 未知操作符，返回错误对象
+		return left == right // 4. 其他对象比较是否为同一实例
+	}
+}
+
+// nativeBoolToBooleanObject 将 Go 布尔值转换为对应的布尔对象
+func nativeBoolToBooleanObject(input bool) *object.Boolean {
+	if input {
+		return TRUE
 	}
+	return FALSE
 }
 
-// newError 创建一个新的错误对象，包含格式化的错误消息
-func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)} // 1. 使用 fmt.Sprintf 格式化错误消息
+// newError 创建一个新的错误对象，包含错误种类和格式化的错误消息
+func newError(kind string, format string, a ...interface{}) *object.Error {
+	return &object.Error{Kind: kind, Message: fmt.Sprintf(format, a...)} // 1. 使用 fmt.Sprintf 格式化错误消息
 }
 
-// isError 检查一个对象是否为错误对象
-func isError(obj object.Object) bool {
+// isAbrupt 检查一个对象是否会中断求值，即错误对象或提前返回的返回值对象
+func isAbrupt(obj object.Object) bool {
 	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ // 1. 如果对象不为 nil，检查其类型是否为 ERROR_OBJ
+		rt := obj.Type()
+		return rt == object.ERROR_OBJ || rt == object.RETURN_VALUE_OBJ // 1. 如果对象不为 nil，检查其类型
 	}
 	return false // 2. 如果对象为 nil，返回 false
 }
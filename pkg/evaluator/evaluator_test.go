@@ -0,0 +1,77 @@
+package evaluator
+
+import (
+	"testing"
+
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/object"
+	"punyGo/pkg/parser"
+)
+
+// testEval 解析并评估一段程序，返回程序的值
+func testEval(t *testing.T, input string) object.Object {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return Eval(program, object.NewEnvironment(nil))
+}
+
+// expectInspect 评估程序并检查结果的字符串表示
+func expectInspect(t *testing.T, input, want string) {
+	t.Helper()
+	if got := testEval(t, input).Inspect(); got != want {
+		t.Errorf("%s: got %s, want %s", input, got, want)
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	expectInspect(t, `"a\"b"`, `a"b`)
+	expectInspect(t, `len("\n")`, "1")
+	expectInspect(t, `let y = 2; "a\t${y} \${y}"`, "a\t2 ${y}")
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let name = "Ann"; let count = 2; "Hello ${name}, you have ${count + 1} items"`, "Hello Ann, you have 3 items"},
+		{`"${1 + 2}${"x"}"`, "3x"},
+		{`let h = {"a": 1}; "v=${h["a"]} ${ {"b": 2}["b"] }"`, "v=1 2"},
+		{`"outer ${ "inner ${ 1 + 1 }" }"`, "outer inner 2"},
+		{`"${[1, "a"]}"`, `[1, "a"]`},
+		{`let f = fn(n) { let m = n * 2; "${n}-${m}" }; f(4)`, "4-8"},
+		{`"no ${missing}"`, "ERROR: identifier not found: missing"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestOptionalChaining(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let h = {"a": {"b": 1}}; h.a.b`, "1"},
+		{`let h = {"a": 1}; h.missing`, "nil"},
+		{`let h = {"a": 1}; h?.a`, "1"},
+		{`let h = {"a": 1}; h?.missing?.b`, "nil"},
+		{`let h = nil; h?.a`, "nil"},
+		{`let h = nil; h?.a.b`, "ERROR: NULL has no member b"},
+		{`let h = {1: 2}; h.a`, "nil"},
+		{`let xs = nil; xs?[0]`, "nil"},
+		{`let xs = [[1, 2]]; xs?[0]?[1]`, "2"},
+		{`let xs = nil; xs?[0:1]`, "nil"},
+		{`let h = {"a": nil}; h.a?.b ?? "default"`, "default"},
+		{`enum E { V(x) }; let v = nil; v?.x`, "nil"},
+		{`enum E { V(x) }; V(3)?.x`, "3"},
+		{`let h = nil; h.a`, "ERROR: NULL has no member a"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,95 @@
+package evaluator
+
+import (
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// newGenerator 调用生成器函数，返回一个尚未开始执行的生成器，env 是已经绑定实参的调用环境，frame 是它的调用帧。
+// 函数体运行在独立的 goroutine 中，但同一时刻只有调用方或函数体之一在运行，
+// 因此二者共享的环境无需加锁；调用方放弃生成器时通过 closeGenerator 结束该 goroutine
+func newGenerator(fn *object.Function, env *object.Environment, frame *object.Frame) *object.Generator {
+	gen := &object.Generator{
+		Name:   fn.Name,
+		Resume: make(chan struct{}),
+		Yield:  make(chan object.Object),
+		Stop:   make(chan struct{}),
+	}
+	frame.Generator = gen // 1. 把调用帧关联到该生成器，函数体中的 yield 通过它找到生成器
+
+	go func() {
+		defer close(gen.Yield) // 5. 函数体结束后关闭通道，表示生成器已经结束
+
+		select { // 2. 等待第一次 next 调用再开始执行，在此之前被关闭则不执行函数体
+		case <-gen.Resume:
+		case <-gen.Stop:
+			return
+		}
+		result := Eval(fn.Body, env) // 3. 执行函数体，期间每次 yield 都会暂停
+		result = runDeferred(frame, result)
+		if errObj, ok := result.(*object.Error); ok { // 4. 函数体中的错误交给 next，生成器已被关闭时交给 closeGenerator
+			gen.Yield <- errObj
+		}
+	}()
+
+	return gen // 6. 返回生成器
+}
+
+// nextGenerator 恢复生成器的执行，返回下一个产出的值；生成器结束后返回 StopIteration 错误
+func nextGenerator(gen *object.Generator) object.Object {
+	if gen.Done { // 1. 已经结束的生成器不能再恢复
+		return newError(object.STOP_ITERATION, "generator %s is exhausted", gen.Name)
+	}
+
+	gen.Resume <- struct{}{} // 2. 恢复函数体的执行
+	val, ok := <-gen.Yield   // 3. 等待函数体产出下一个值或结束
+	if !ok {                 // 4. 通道已关闭，说明函数体执行完毕
+		gen.Done = true
+		return newError(object.STOP_ITERATION, "generator %s is exhausted", gen.Name)
+	}
+	if _, isErr := val.(*object.Error); isErr { // 5. 函数体出错后生成器随之结束
+		gen.Done = true
+	}
+
+	return val // 6. 返回产出的值或错误
+}
+
+// closeGenerator 在调用方放弃生成器时结束它：暂停中的 yield 得到 GeneratorExit 错误，
+// 函数体随之展开并执行 defer。等待函数体结束后返回，展开期间出现的其他错误作为返回值
+func closeGenerator(gen *object.Generator) object.Object {
+	if gen.Done { // 1. 已经结束的生成器无需关闭
+		return nil
+	}
+	gen.Done = true
+	close(gen.Stop) // 2. 通知函数体结束
+
+	var result object.Object
+	for val := range gen.Yield { // 3. 等待函数体展开完毕，忽略此期间产出的值
+		if errObj, ok := val.(*object.Error); ok && errObj.Kind != object.GENERATOR_EXIT {
+			result = errObj
+		}
+	}
+	return result
+}
+
+// evalYieldStatement 评估 yield 语句，把值交给调用方并暂停，直到生成器被再次恢复
+func evalYieldStatement(node *ast.YieldStatement, env *object.Environment) object.Object {
+	frame := env.Frame()
+	if frame == nil || frame.Generator == nil { // 1. yield 只能出现在生成器函数中
+		return newError(object.TYPE_ERROR, "yield outside generator function")
+	}
+
+	val := Eval(node.Value, env) // 2. 评估产出的值
+	if isAbrupt(val) {
+		return val
+	}
+
+	gen := frame.Generator
+	gen.Yield <- val // 3. 把值交给调用方
+	select {         // 4. 等待下一次恢复，生成器被关闭时以 GeneratorExit 错误展开函数体
+	case <-gen.Resume:
+	case <-gen.Stop:
+		return newError(object.GENERATOR_EXIT, "generator %s is closed", gen.Name)
+	}
+	return NULL // 5. yield 语句本身没有值
+}
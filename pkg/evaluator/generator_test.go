@@ -0,0 +1,54 @@
+package evaluator
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	gen := "let count = fn*(n) { for i in 0..n { yield i } }; "
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{gen + "let g = count(2); [next(g), next(g)]", "[0, 1]"},
+		{gen + "let g = count(1); next(g); try { next(g) } catch (e) { e.kind }", "StopIteration"},
+		{gen + "let g = count(0); try { next(g) } catch (e) { e.kind }; try { next(g) } catch (e) { e.kind }", "StopIteration"},
+		{gen + "[x * 2 for x in count(3)]", "[0, 2, 4]"},
+		{"let g = fn*() { yield 1; throw \"boom\" }; let it = g(); next(it); try { next(it) } catch (e) { e.message }", "boom"},
+		{"let g = fn*() { yield 1; return 5; yield 2 }; [x for x in g()]", "[1]"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestAbandonedGeneratorRunsDefers(t *testing.T) {
+	gen := `let c = chan(2); let g = fn*() { defer send(c, "cleaned"); yield 1; yield 2 }; `
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{gen + "let first = fn() { for x in g() { return x } }; [first(), recv(c)]", `[1, "cleaned"]`},
+		{gen + "let it = g(); next(it); close(it); let cleaned = recv(c); try { next(it) } catch (e) { [cleaned, e.kind] }", `["cleaned", "StopIteration"]`},
+		{gen + "let it = g(); close(it); send(c, \"unstarted\"); recv(c)", "unstarted"},
+		{gen + "let it = g(); [x for x in it]; close(it); recv(c)", "cleaned"},
+		{"let fail = fn() { throw \"in defer\" }; let g = fn*() { defer fail(); yield 1 }; let it = g(); next(it); try { close(it) } catch (e) { e.message }", "in defer"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestClosedGeneratorsDoNotLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	testEval(t, `let g = fn*() { yield 1; yield 2 }; [close(g()) for i in 0..100]; [close(g()) for i in 0..100 if next(g()) == 0]`)
+	for i := 0; i < 100 && runtime.NumGoroutine() > before+100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	expectInspect(t, `let g = fn*() { yield 1; yield 2 }; let it = g(); next(it); close(it)`, "<generator g>")
+	if after := runtime.NumGoroutine(); after > before+100 {
+		t.Errorf("goroutines: %d before, %d after", before, after)
+	}
+}
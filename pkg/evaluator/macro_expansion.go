@@ -0,0 +1,219 @@
+package evaluator
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// hygieneCounter 用于为宏引入的绑定生成唯一的名字
+var hygieneCounter atomic.Int64
+
+// DefineMacros 把程序顶层的 let name = macro(...) { ... } 定义放入宏环境，并从程序中移除
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	definitions := []int{}
+
+	for i, statement := range program.Statements { // 1. 找出所有宏定义
+		if isMacroDefinition(statement) {
+			addMacro(statement, env)
+			definitions = append(definitions, i)
+		}
+	}
+
+	for i := len(definitions) - 1; i >= 0; i-- { // 2. 从后向前移除宏定义，避免下标错位
+		idx := definitions[i]
+		program.Statements = append(program.Statements[:idx], program.Statements[idx+1:]...)
+	}
+}
+
+// isMacroDefinition 判断语句是否为宏定义
+func isMacroDefinition(node ast.Statement) bool {
+	letStatement, ok := node.(*ast.LetStatement)
+	if !ok || letStatement.Name == nil {
+		return false
+	}
+	_, ok = letStatement.Value.(*ast.MacroLiteral)
+	return ok
+}
+
+// addMacro 创建宏对象并绑定到宏环境中
+func addMacro(stmt ast.Statement, env *object.Environment) {
+	letStatement := stmt.(*ast.LetStatement)
+	macroLiteral := letStatement.Value.(*ast.MacroLiteral)
+
+	macro := &object.Macro{
+		Parameters: macroLiteral.Parameters,
+		Env:        env,
+		Body:       macroLiteral.Body,
+	}
+	env.Set(letStatement.Name.Value, macro)
+}
+
+// ExpandMacros 展开程序中所有的宏调用，返回展开后的新程序，原程序保持不变
+func ExpandMacros(program *ast.Program, env *object.Environment) (*ast.Program, *object.Error) {
+	var err *object.Error
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		if err != nil { // 1. 出错后不再继续展开
+			return node
+		}
+
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+		macro, ok := isMacroCall(call, env) // 2. 只处理对宏的调用
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) != len(macro.Parameters) { // 3. 检查实参数量
+			err = newError(object.ARGUMENT_ERROR, "wrong number of arguments for macro %s: want=%d, got=%d",
+				call.Function, len(macro.Parameters), len(call.Arguments))
+			return node
+		}
+
+		args := quoteArgs(call)                // 4. 实参以未求值的 AST 传入
+		evalEnv := extendMacroEnv(macro, args) // 5. 在宏环境中绑定形参
+		evaluated := Eval(macro.Body, evalEnv) // 6. 求值宏体
+		evaluated = unwrapReturnValue(evaluated)
+
+		if errObj, isErr := evaluated.(*object.Error); isErr {
+			err = errObj
+			return node
+		}
+		quote, ok := evaluated.(*object.Quote) // 7. 宏必须返回 AST
+		if !ok || quote.Node == nil {
+			err = newError(object.MACRO_ERROR, "macro %s must return a quoted AST, got %s", call.Function, typeOf(evaluated))
+			return node
+		}
+
+		return hygienic(quote.Node, call.Arguments) // 8. 重命名宏引入的绑定后替换宏调用
+	})
+
+	if err != nil {
+		return program, err
+	}
+	return expanded.(*ast.Program), nil
+}
+
+// isMacroCall 判断调用是否为对宏的调用
+func isMacroCall(exp *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	identifier, ok := exp.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(identifier.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	return macro, ok
+}
+
+// quoteArgs 把调用的实参包装为 Quote 对象
+func quoteArgs(exp *ast.CallExpression) []*object.Quote {
+	args := []*object.Quote{}
+	for _, a := range exp.Arguments {
+		args = append(args, &object.Quote{Node: a})
+	}
+	return args
+}
+
+// extendMacroEnv 创建宏调用环境，并将 Quote 实参绑定到形参
+func extendMacroEnv(macro *object.Macro, args []*object.Quote) *object.Environment {
+	extended := object.NewEnvironment(macro.Env)
+	for i, param := range macro.Parameters {
+		extended.Set(param.Value, args[i])
+	}
+	return extended
+}
+
+// hygienic 重命名宏展开结果中由宏自身引入的绑定及其引用，使它们不会与调用者的变量冲突。
+// 来自实参的标识符保持原样，它们仍然指向调用处的变量
+func hygienic(node ast.Node, args []ast.Expression) ast.Node {
+	fromCaller := map[*ast.Identifier]bool{} // 1. 记录实参中出现的所有标识符
+	for _, arg := range args {
+		ast.Modify(arg, func(n ast.Node) ast.Node {
+			if ident, ok := n.(*ast.Identifier); ok {
+				fromCaller[ident] = true
+			}
+			return n
+		})
+	}
+
+	renamed := map[string]string{} // 2. 为宏引入的绑定生成新名字
+	bind := func(ident *ast.Identifier) {
+		if ident != nil && !fromCaller[ident] {
+			if _, ok := renamed[ident.Value]; !ok {
+				renamed[ident.Value] = fmt.Sprintf("%s__%d", ident.Value, hygieneCounter.Add(1))
+			}
+		}
+	}
+	ast.Modify(node, func(n ast.Node) ast.Node {
+		switch n := n.(type) {
+		case *ast.LetStatement:
+			bind(n.Name)
+			for _, name := range n.Names {
+				bind(name)
+			}
+		case *ast.FunctionLiteral:
+			for _, p := range n.Parameters {
+				bind(p)
+			}
+			bind(n.Rest)
+		case *ast.TryStatement:
+			bind(n.CatchParam)
+		case *ast.ForStatement:
+			bind(n.Variable)
+		case *ast.ListComprehension:
+			bind(n.Clause.Variable)
+			for _, name := range n.Clause.Names {
+				bind(name)
+			}
+		case *ast.HashComprehension:
+			bind(n.Clause.Variable)
+			for _, name := range n.Clause.Names {
+				bind(name)
+			}
+		case *ast.SelectStatement:
+			for _, c := range n.Cases {
+				bind(c.Name)
+			}
+		case *ast.MatchExpression:
+			for _, c := range n.Cases {
+				for _, name := range c.Names {
+					bind(name)
+				}
+			}
+		}
+		return n
+	})
+
+	if len(renamed) == 0 {
+		return node
+	}
+
+	return ast.Modify(node, func(n ast.Node) ast.Node { // 3. 替换宏自身的标识符
+		ident, ok := n.(*ast.Identifier)
+		if !ok || fromCaller[ident] {
+			return n
+		}
+		if name, ok := renamed[ident.Value]; ok {
+			return &ast.Identifier{Token: ident.Token, Value: name}
+		}
+		return n
+	})
+}
+
+// typeOf 返回对象的类型，nil 表示没有值
+func typeOf(obj object.Object) string {
+	if obj == nil {
+		return "nothing"
+	}
+	return string(obj.Type())
+}
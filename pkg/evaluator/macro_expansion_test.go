@@ -0,0 +1,76 @@
+package evaluator
+
+import (
+	"testing"
+
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/object"
+	"punyGo/pkg/parser"
+)
+
+// expandAndEval 定义并展开程序中的宏，然后评估展开后的程序；展开失败时返回展开错误
+func expandAndEval(t *testing.T, input string) object.Object {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	macros := object.NewEnvironment(nil)
+	DefineMacros(program, macros)
+	expanded, err := ExpandMacros(program, macros)
+	if err != nil {
+		return err
+	}
+	return Eval(expanded, object.NewEnvironment(nil))
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`quote(1 + 2)`, "QUOTE((1 + 2))"},
+		{`quote(fn(x) { x })`, "QUOTE(fn(x) { x })"},
+		{`let x = 5; quote(unquote(x) + 1)`, "QUOTE((5 + 1))"},
+		{`quote(unquote(quote(a + b)) * 2)`, "QUOTE(((a + b) * 2))"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestMacroExpansion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let unless = macro(cond, cons, alt) { quote(if (!(unquote(cond))) { unquote(cons) } else { unquote(alt) }) }; unless(10 > 5, "no", "yes")`, "yes"},
+		{`let m = macro(x) { quote(unquote(x) * 2) }; m(3 + 1)`, "8"},
+		{`let m = macro(a, b) { quote(unquote(a)) }; m(1)`, "ERROR: wrong number of arguments for macro m: want=2, got=1"},
+		{`let m = macro(x) { 5 }; m(1)`, "ERROR: macro m must return a quoted AST, got INTEGER"},
+	}
+	for _, tt := range tests {
+		if got := expandAndEval(t, tt.input).Inspect(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMacroHygiene(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let double = macro(e) { quote(fn() { let n = unquote(e); n * 2 }()) }; let n = 7; double(n)`, "14"},
+		{`let m = macro(e) { quote(fn() { let hidden = 1; unquote(e) }()) }; let hidden = 5; m(hidden)`, "5"},
+		{`let m = macro(e) { quote(fn(x) { x + unquote(e) }(1)) }; let x = 10; m(x)`, "11"},
+		{`let m = macro(e) { quote([y * unquote(e) for y in [1, 2]]) }; let y = 10; m(y)`, "[10, 20]"},
+		{`let m = macro(e) { quote(match Ok(1) { case Ok(v) { v + unquote(e) } }) }; let v = 10; m(v)`, "11"},
+	}
+	for _, tt := range tests {
+		if got := expandAndEval(t, tt.input).Inspect(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
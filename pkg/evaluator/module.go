@@ -0,0 +1,219 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/object"
+	"punyGo/pkg/parser"
+	"punyGo/pkg/types"
+)
+
+// SEARCH_PATH_ENV 是模块搜索路径的环境变量，多个目录之间用系统的路径分隔符隔开
+const SEARCH_PATH_ENV = "PUNYGO_PATH"
+
+// 已导入模块的缓存，以模块文件的绝对路径为键
+var (
+	moduleMu    sync.Mutex
+	moduleCache = map[string]*object.Module{}
+)
+
+// RunFile 将文件作为主模块求值，返回最后一条语句的结果或错误对象
+func RunFile(path string) object.Object {
+	abs, err := filepath.Abs(path) // 1. 主模块同样以绝对路径缓存，避免被其他模块重复导入
+	if err != nil {
+		return newError(object.IMPORT_ERROR, "cannot resolve %s: %s", path, err)
+	}
+
+	module := newModule(abs, nil) // 2. 创建主模块并放入缓存
+	moduleMu.Lock()
+	moduleCache[abs] = module
+	moduleMu.Unlock()
+
+	return loadModule(module) // 3. 求值主模块
+}
+
+// evalImportStatement 评估 import 语句，把模块对象绑定到别名上
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	module := importModule(node.Path.Value, env.Module()) // 1. 解析并加载模块
+	if isAbrupt(module) {
+		return module
+	}
+
+	env.Set(node.Alias.Value, module) // 2. 在环境中绑定模块
+	return NULL                       // 3. 声明语句没有值
+}
+
+// evalExportStatement 评估 export 声明，只允许出现在模块的顶层
+func evalExportStatement(node *ast.ExportStatement, env *object.Environment) object.Object {
+	module := env.Module()
+	if module == nil || module.Env != env { // 1. 只有模块顶层的声明才能导出
+		return newError(object.IMPORT_ERROR, "export is only allowed at the top level of a module")
+	}
+
+	result := Eval(node.Statement, env) // 2. 评估被导出的声明
+	if isAbrupt(result) {
+		return result
+	}
+
+	switch decl := node.Statement.(type) { // 3. 记录声明引入的名字
+	case *ast.LetStatement:
+		if decl.Name != nil {
+			module.Exports[decl.Name.Value] = true
+		}
+		for _, name := range decl.Names { // 3.0.1. 解构赋值导出所有变量
+			module.Exports[name.Value] = true
+		}
+	case *ast.FunctionStatement:
+		module.Exports[decl.Function.Name] = true
+	case *ast.EnumStatement:
+		module.Exports[decl.Name.Value] = true // 3.1. 枚举同时导出类型和所有变体
+		for _, v := range decl.Variants {
+			module.Exports[v.Name.Value] = true
+		}
+	}
+
+	return result // 4. 返回声明的结果
+}
+
+// evalModuleMember 评估模块成员访问，只能访问被导出的名字
+func evalModuleMember(module *object.Module, name string) object.Object {
+	if !module.Exports[name] { // 1. 未导出的名字对外不可见
+		return newError(object.NAME_ERROR, "module %s has no exported member %s", module.Name, name)
+	}
+
+	val, _ := module.Env.Get(name) // 2. 从模块环境中读取当前的值
+	return val
+}
+
+// importModule 解析模块路径并返回模块对象，模块只会被求值一次
+func importModule(path string, importer *object.Module) object.Object {
+	abs, ok := resolveModulePath(path, importer) // 1. 查找模块文件
+	if !ok {
+		return newError(object.IMPORT_ERROR, "module not found: %s", path)
+	}
+
+	moduleMu.Lock()
+	module, cached := moduleCache[abs] // 2. 查找缓存
+	if !cached {
+		module = newModule(abs, importer)
+		moduleCache[abs] = module
+	}
+	moduleMu.Unlock()
+
+	if !cached { // 3. 第一次导入时求值模块
+		if result := loadModule(module); isAbrupt(result) {
+			return result
+		}
+		return module
+	}
+
+	if cycle := importCycle(module, importer); cycle != "" { // 4. 导入仍在求值中的祖先模块说明存在循环导入
+		return newError(object.IMPORT_ERROR, "import cycle: %s", cycle)
+	}
+
+	<-module.Loaded // 5. 等待其他任务中正在进行的求值
+	if module.Err != nil {
+		return module.Err
+	}
+	return module
+}
+
+// newModule 创建一个尚未求值的模块对象
+func newModule(abs string, importer *object.Module) *object.Module {
+	module := &object.Module{
+		Name:     strings.TrimSuffix(filepath.Base(abs), filepath.Ext(abs)),
+		Path:     abs,
+		Exports:  map[string]bool{},
+		Importer: importer,
+		Loaded:   make(chan struct{}),
+	}
+	module.Env = object.NewModuleEnvironment(module)
+	return module
+}
+
+// loadModule 读取、解析并在模块自己的环境中求值模块文件
+func loadModule(module *object.Module) object.Object {
+	defer close(module.Loaded) // 1. 无论成功与否都通知等待者
+
+	result := evalModuleFile(module) // 2. 求值模块文件
+	if errObj, ok := result.(*object.Error); ok {
+		module.Err = errObj // 3. 记录错误，正在等待的导入会得到同样的错误
+
+		moduleMu.Lock()
+		delete(moduleCache, module.Path) // 4. 移出缓存，修正后可以重新导入
+		moduleMu.Unlock()
+	}
+	return result
+}
+
+// evalModuleFile 读取并解析模块文件，然后在模块的顶层环境中求值
+func evalModuleFile(module *object.Module) object.Object {
+	source, err := os.ReadFile(module.Path) // 1. 读取模块文件
+	if err != nil {
+		return newError(object.IMPORT_ERROR, "cannot read module %s: %s", module.Path, err)
+	}
+
+	par := parser.New(lexer.New(string(source))) // 2. 解析模块文件
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		return newError(object.SYNTAX_ERROR, "%s: %s", module.Path, strings.Join(par.Errors(), "; "))
+	}
+
+	macroEnv := object.NewEnvironment(nil) // 3. 宏只在定义它的模块内展开
+	DefineMacros(program, macroEnv)
+	expanded, errObj := ExpandMacros(program, macroEnv)
+	if errObj != nil {
+		return errObj
+	}
+
+	if errs := types.Check(expanded); len(errs) != 0 { // 4. 求值之前先进行静态类型检查
+		return newError(object.TYPE_ERROR, "%s: %s", module.Path, strings.Join(errs, "; "))
+	}
+
+	return Eval(expanded, module.Env) // 5. 在模块的顶层环境中求值
+}
+
+// resolveModulePath 依次在导入者所在目录和搜索路径中查找模块文件，返回其绝对路径
+func resolveModulePath(path string, importer *object.Module) (string, bool) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		base := "." // 1. REPL 中相对于当前工作目录
+		if importer != nil {
+			base = filepath.Dir(importer.Path) // 2. 模块中相对于模块文件所在目录
+		}
+		candidates = []string{filepath.Join(base, path)}
+
+		for _, dir := range filepath.SplitList(os.Getenv(SEARCH_PATH_ENV)) { // 3. 然后依次查找搜索路径
+			candidates = append(candidates, filepath.Join(dir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			if abs, err := filepath.Abs(candidate); err == nil {
+				return abs, true // 4. 返回第一个存在的文件
+			}
+		}
+	}
+	return "", false
+}
+
+// importCycle 如果 module 出现在导入者的导入链上，返回描述循环的字符串，例如 "a.pg -> b.pg -> a.pg"
+func importCycle(module *object.Module, importer *object.Module) string {
+	chain := []string{}
+	for m := importer; m != nil; m = m.Importer { // 1. 沿导入链向上查找
+		chain = append(chain, filepath.Base(m.Path))
+		if m == module { // 2. 找到被导入的模块，说明形成了循环
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+			return strings.Join(append(chain, filepath.Base(module.Path)), " -> ")
+		}
+	}
+	return "" // 3. 没有循环
+}
@@ -0,0 +1,76 @@
+package evaluator
+
+import (
+	"punyGo/pkg/object"
+)
+
+// operatorMethods 记录可以被重载的中缀操作符及其特殊方法名
+var operatorMethods = map[string]string{
+	"+":  "__add__",
+	"-":  "__sub__",
+	"*":  "__mul__",
+	"/":  "__div__",
+	"==": "__eq__",
+	"!=": "__ne__",
+	"<":  "__lt__",
+	">":  "__gt__",
+}
+
+// reflectedMethods 记录左侧操作数没有实现特殊方法时，在右侧操作数上查找的方法名。
+// 算术操作符使用 __radd__ 这样的反射方法，比较操作符使用方向相反的比较
+var reflectedMethods = map[string]string{
+	"+":  "__radd__",
+	"-":  "__rsub__",
+	"*":  "__rmul__",
+	"/":  "__rdiv__",
+	"==": "__eq__",
+	"!=": "__ne__",
+	"<":  "__gt__",
+	">":  "__lt__",
+}
+
+// lookupMethod 在枚举值所属枚举的方法表中查找方法，其他对象没有方法
+func lookupMethod(obj object.Object, name string) (*object.Function, bool) {
+	ev, ok := obj.(*object.EnumValue)
+	if !ok {
+		return nil, false
+	}
+	return ev.Variant.Enum.Method(name)
+}
+
+// bindMethod 将方法与接收者绑定，调用时接收者作为第一个实参传入
+func bindMethod(receiver object.Object, method *object.Function) *object.Builtin {
+	return &object.Builtin{
+		Name: method.Name,
+		Fn: func(args ...object.Object) object.Object {
+			return applyFunction(method, append([]object.Object{receiver}, args...), nil)
+		},
+	}
+}
+
+// evalOperatorMethod 通过特殊方法评估中缀操作符，先查找左侧操作数的方法，再查找右侧操作数的反射方法。
+// 两侧都没有实现时返回 false，由调用方回退到内置行为。只实现了 __eq__ 时，!= 取其结果的否定
+func evalOperatorMethod(operator string, left, right object.Object) (object.Object, bool) {
+	name, ok := operatorMethods[operator]
+	if !ok { // 1. 该操作符不能被重载
+		return nil, false
+	}
+
+	if method, ok := lookupMethod(left, name); ok { // 2. 左侧操作数的方法
+		return applyFunction(method, []object.Object{left, right}, nil), true
+	}
+	if method, ok := lookupMethod(right, reflectedMethods[operator]); ok { // 3. 右侧操作数的反射方法
+		return applyFunction(method, []object.Object{right, left}, nil), true
+	}
+
+	if operator == "!=" { // 4. 由 __eq__ 推导 !=
+		if result, ok := evalOperatorMethod("==", left, right); ok {
+			if isAbrupt(result) {
+				return result, true
+			}
+			return nativeBoolToBooleanObject(!isTruthy(result)), true
+		}
+	}
+
+	return nil, false // 5. 回退到内置行为
+}
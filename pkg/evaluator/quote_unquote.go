@@ -0,0 +1,91 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+	"punyGo/pkg/token"
+)
+
+// quote 返回未求值的 AST，其中的 unquote(...) 调用会先被求值并替换为对应的 AST
+func quote(node ast.Node, env *object.Environment) object.Object {
+	node, err := evalUnquoteCalls(node, env) // 1. 求值并替换 unquote 调用
+	if err != nil {
+		return err
+	}
+	return &object.Quote{Node: node} // 2. 包装为 Quote 对象
+}
+
+// evalUnquoteCalls 求值 AST 中所有的 unquote 调用，并把结果转换回 AST 节点
+func evalUnquoteCalls(quoted ast.Node, env *object.Environment) (ast.Node, object.Object) {
+	var err object.Object
+
+	node := ast.Modify(quoted, func(node ast.Node) ast.Node {
+		if err != nil || !isUnquoteCall(node) { // 1. 只处理 unquote 调用，出错后不再继续求值
+			return node
+		}
+
+		call := node.(*ast.CallExpression)
+		if len(call.Arguments) != 1 { // 2. unquote 只接受一个实参
+			err = newError(object.ARGUMENT_ERROR, "wrong number of arguments for unquote: want=1, got=%d", len(call.Arguments))
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env) // 3. 在当前环境中求值实参
+		if isAbrupt(unquoted) {
+			err = unquoted
+			return node
+		}
+
+		converted, ok := convertObjectToASTNode(unquoted) // 4. 把求值结果转换回 AST 节点
+		if !ok {
+			err = newError(object.MACRO_ERROR, "cannot unquote %s into the AST", unquoted.Type())
+			return node
+		}
+		return converted
+	})
+
+	return node, err
+}
+
+// isUnquoteCall 判断节点是否为 unquote(...) 调用
+func isUnquoteCall(node ast.Node) bool {
+	call, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+	return call.Function.TokenLiteral() == "unquote"
+}
+
+// convertObjectToASTNode 把可以用字面量表示的对象转换为 AST 节点
+func convertObjectToASTNode(obj object.Object) (ast.Node, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		t := token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", obj.Value)}
+		return &ast.IntegerLiteral{Token: t, Value: obj.Value}, true // 1. 整数转换为整数字面量
+	case *object.Boolean:
+		t := token.Token{Type: token.FALSE, Literal: "false"}
+		if obj.Value {
+			t = token.Token{Type: token.TRUE, Literal: "true"}
+		}
+		return &ast.Boolean{Token: t, Value: obj.Value}, true // 2. 布尔值转换为布尔字面量
+	case *object.String:
+		t := token.Token{Type: token.STRING, Literal: obj.Value}
+		return &ast.StringLiteral{Token: t, Value: obj.Value}, true // 3. 字符串转换为字符串字面量
+	case *object.Bytes:
+		inspected := obj.Inspect()
+		t := token.Token{Type: token.BYTES, Literal: inspected[2 : len(inspected)-1]}
+		return &ast.BytesLiteral{Token: t, Value: obj.Value}, true // 3.0.1. 字节串转换为字节串字面量
+	case *object.Char:
+		inspected := obj.Inspect()
+		t := token.Token{Type: token.CHAR, Literal: inspected[1 : len(inspected)-1]}
+		return &ast.CharLiteral{Token: t, Value: obj.Value}, true // 3.0.2. 字符转换为字符字面量
+	case *object.Null:
+		return &ast.NilLiteral{Token: token.Token{Type: token.NIL, Literal: "nil"}}, true // 3.1. 空值转换为 nil 字面量
+	case *object.Quote:
+		return obj.Node, true // 4. Quote 直接展开为其中的 AST
+	default:
+		return nil, false // 5. 其他对象无法表示为 AST
+	}
+}
@@ -0,0 +1,143 @@
+package evaluator
+
+import (
+	"regexp"
+	"strings"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// evalRegexLiteral 编译正则表达式字面量，标志以 "(?flags)" 的形式合并到模式前。
+// 模式无法编译时返回带有字面量位置的错误
+func evalRegexLiteral(node *ast.RegexLiteral) object.Object {
+	pattern := node.Pattern
+	if node.Flags != "" {
+		pattern = "(?" + node.Flags + ")" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		if node.Token.Line > 0 {
+			return newError(object.REGEX_ERROR, "%d:%d: invalid regex %s: %s", node.Token.Line, node.Token.Column, node.String(), err)
+		}
+		return newError(object.REGEX_ERROR, "invalid regex %s: %s", node.String(), err)
+	}
+	return &object.Regex{Pattern: node.Pattern, Flags: node.Flags, Regexp: re}
+}
+
+// evalMatchExpression 评估 "s =~ re"，判断字符串或字节串中是否存在匹配
+func evalMatchExpression(left, right object.Object) object.Object {
+	re, ok := right.(*object.Regex)
+	if !ok {
+		return newError(object.TYPE_ERROR, "right operand of =~ must be REGEX, got %s", right.Type())
+	}
+	switch left := left.(type) {
+	case *object.String:
+		return nativeBoolToBooleanObject(re.Regexp.MatchString(left.Value))
+	case *object.Bytes:
+		return nativeBoolToBooleanObject(re.Regexp.Match(left.Value))
+	default:
+		return newError(object.TYPE_ERROR, "left operand of =~ must be STRING or BYTES, got %s", left.Type())
+	}
+}
+
+// regexMember 访问正则表达式的成员：pattern 和 flags 属性，以及 match、find_all 和 replace 方法；其他名字返回 nil
+func regexMember(re *object.Regex, name string) object.Object {
+	switch name {
+	case "pattern":
+		return &object.String{Value: re.Pattern}
+	case "flags":
+		return &object.String{Value: re.Flags}
+	case "match":
+		return &object.Builtin{Name: name, Fn: func(args ...object.Object) object.Object {
+			s, errObj := regexSubject(name, 1, args)
+			if errObj != nil {
+				return errObj
+			}
+			return regexMatch(re.Regexp, s)
+		}}
+	case "find_all":
+		return &object.Builtin{Name: name, Fn: func(args ...object.Object) object.Object {
+			s, errObj := regexSubject(name, 1, args)
+			if errObj != nil {
+				return errObj
+			}
+			elements := []object.Object{}
+			for _, m := range re.Regexp.FindAllString(s, -1) {
+				elements = append(elements, &object.String{Value: m})
+			}
+			return &object.Array{Elements: elements}
+		}}
+	case "replace":
+		return &object.Builtin{Name: name, Fn: func(args ...object.Object) object.Object {
+			s, errObj := regexSubject(name, 2, args)
+			if errObj != nil {
+				return errObj
+			}
+			return regexReplace(re.Regexp, s, args[1])
+		}}
+	}
+	return nil
+}
+
+// regexSubject 检查正则表达式方法的实参个数，并返回第一个实参，即被匹配的字符串
+func regexSubject(name string, want int, args []object.Object) (string, *object.Error) {
+	if len(args) != want {
+		return "", newError(object.ARGUMENT_ERROR, "wrong number of arguments for %s: want=%d, got=%d", name, want, len(args))
+	}
+	s, ok := args[0].(*object.String)
+	if !ok {
+		return "", newError(object.TYPE_ERROR, "argument to %s must be STRING, got %s", name, args[0].Type())
+	}
+	return s.Value, nil
+}
+
+// regexMatch 返回第一个匹配的捕获组，没有匹配时返回 nil。
+// 结果是一个哈希：整数键 0 是整个匹配，1、2…… 是各个捕获组，具名捕获组同时以组名为键；未参与匹配的捕获组为 nil
+func regexMatch(re *regexp.Regexp, s string) object.Object {
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+
+	groups := object.NewHash()
+	names := re.SubexpNames()
+	for i := 0; i < len(loc)/2; i++ {
+		var val object.Object = NULL
+		if loc[2*i] >= 0 {
+			val = &object.String{Value: s[loc[2*i]:loc[2*i+1]]}
+		}
+		groups.Set(&object.Integer{Value: int64(i)}, val) // 1. 按编号记录捕获组
+		if names[i] != "" {
+			groups.Set(&object.String{Value: names[i]}, val) // 2. 具名捕获组同时按组名记录
+		}
+	}
+	return groups
+}
+
+// regexReplace 替换所有匹配。替换值是字符串时可以用 $1 或 ${name} 引用捕获组；
+// 是函数时以匹配的字符串调用它，返回值必须是字符串
+func regexReplace(re *regexp.Regexp, s string, repl object.Object) object.Object {
+	if r, ok := repl.(*object.String); ok {
+		return &object.String{Value: re.ReplaceAllString(s, r.Value)} // 1. 按模板替换
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(s, -1) { // 2. 依次调用函数计算替换值，出错时停止
+		result := applyFunction(repl, []object.Object{&object.String{Value: s[loc[0]:loc[1]]}}, nil)
+		if isAbrupt(result) {
+			return result
+		}
+		str, ok := result.(*object.String)
+		if !ok {
+			return newError(object.TYPE_ERROR, "replacement function must return STRING, got %s", result.Type())
+		}
+		out.WriteString(s[last:loc[0]])
+		out.WriteString(str.Value)
+		last = loc[1]
+	}
+	out.WriteString(s[last:])
+	return &object.String{Value: out.String()}
+}
@@ -0,0 +1,41 @@
+package evaluator
+
+import "testing"
+
+func TestRegexMatchOperator(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"abc" =~ re"b"`, "true"},
+		{`"ABC" =~ re"b"`, "false"},
+		{`"ABC" =~ re"b"i`, "true"},
+		{`b"ab" =~ re"b"`, "true"},
+		{`10 / 2 / 5`, "1"},
+		{`5 =~ re"b"`, "ERROR: left operand of =~ must be STRING or BYTES, got INTEGER"},
+		{`"a" =~ "a"`, "ERROR: right operand of =~ must be REGEX, got STRING"},
+		{"let x = 1;\n  re\"(unclosed\"", "ERROR: 2:3: invalid regex re\"(unclosed\": error parsing regexp: missing closing ): `(unclosed`"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestRegexMethods(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`re"\d+".find_all("a1 b22 c333")`, `["1", "22", "333"]`},
+		{`re"(\d+)".replace("a1 b22", "<$1>")`, "a<1> b<22>"},
+		{`re"\d+".replace("a1 b22", fn(m) { m + m })`, "a11 b2222"},
+		{`re"(\d+)".match("x12")`, `{0: "12", 1: "12"}`},
+		{`re"(\d+)".match("xx")`, "nil"},
+		{`let m = re"(?P<y>\d{4})-(?P<m>\d\d)".match("on 2024-05"); [m["y"], m["m"], m[0]]`, `["2024", "05", "2024-05"]`},
+		{`re"a".find_all(1)`, "ERROR: argument to find_all must be STRING, got INTEGER"},
+		{`re"a".nope`, "ERROR: REGEX has no member nope"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,27 @@
+package evaluator
+
+import "testing"
+
+func TestResultPropagation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`Ok(1)`, "Ok(1)"},
+		{`Err("bad")`, `Err("bad")`},
+		{`[Ok(1) == Ok(1), Ok(1) == Err(1), Ok(1) is Result]`, "[true, false, true]"},
+		{`[Ok(1).value, Err("x").error]`, `[1, "x"]`},
+		{`let f = fn(x) { if (x > 0) { Ok(x) } else { Err("neg") } }; let g = fn(x) { let v = f(x)?; Ok(v * 2) }; [g(2), g(-1)]`, `[Ok(4), Err("neg")]`},
+		{`let f = fn(r) { r? + 1 }; [f(Ok(1)), f(Err("e"))]`, `[2, Err("e")]`},
+		{`let g = fn() { let inner = fn() { Err(1)? }; let r = inner(); Ok(r) }; g()`, "Ok(Err(1))"},
+		{`let f = fn(r) { for x in [1, 2] { r?; }; 0 }; [f(Ok(3)), f(Err(3))]`, "[0, Err(3)]"},
+		{`let f = fn(r) { try { r? } catch (e) { 1 }; 0 }; f(Err(3))`, "Err(3)"},
+		{`let g = fn(x) { (Ok(x)?).a }; g({"a": 3})`, "3"},
+		{`Ok(1)?`, "1"},
+		{`Err(2)?`, "Err(2)"},
+		{`let g = fn() { 5? }; g()`, "ERROR: operator ? expects Ok or Err, got 5"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,424 @@
+package evaluator
+
+import (
+	"bytes"
+	"strings"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// evalRangeExpression 评估区间表达式，起点、终点和步长都必须是整数，步长默认为 1
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	bounds := []ast.Expression{node.Start, node.End}
+	if node.Step != nil {
+		bounds = append(bounds, node.Step)
+	}
+
+	values := []int64{}
+	for _, b := range bounds { // 1. 依次评估起点、终点和步长
+		val := Eval(b, env)
+		if isAbrupt(val) {
+			return val
+		}
+		n, ok := val.(*object.Integer)
+		if !ok {
+			return newError(object.TYPE_ERROR, "range bounds must be INTEGER, got %s", val.Type())
+		}
+		values = append(values, n.Value)
+	}
+
+	r := &object.Range{Start: values[0], End: values[1], Step: 1, Inclusive: node.Inclusive}
+	if len(values) == 3 { // 2. 设置步长，步长不能为 0
+		if values[2] == 0 {
+			return newError(object.ARGUMENT_ERROR, "range step cannot be zero")
+		}
+		r.Step = values[2]
+	}
+
+	return r // 3. 返回区间对象，元素在遍历时才计算
+}
+
+// evalHashLiteral 评估哈希字面量，各项按书写顺序求值，展开项复制另一个哈希的所有键值对，后出现的键覆盖先出现的键
+func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for _, entry := range node.Entries {
+		if spread, ok := entry.Key.(*ast.SpreadExpression); ok { // 1. 展开另一个哈希
+			val := Eval(spread.Value, env)
+			if isAbrupt(val) {
+				return val
+			}
+			other, ok := val.(*object.Hash)
+			if !ok {
+				return newError(object.TYPE_ERROR, "cannot spread %s into a hash", val.Type())
+			}
+			for _, k := range other.Keys {
+				pair := other.Pairs[k]
+				hash.Set(pair.Key.(object.Hashable), pair.Value)
+			}
+			continue
+		}
+
+		key := Eval(entry.Key, env) // 2. 评估键，键必须可以作为哈希键
+		if isAbrupt(key) {
+			return key
+		}
+		hashKey, ok := hashable(key)
+		if !ok {
+			return newError(object.TYPE_ERROR, "unusable as hash key: %s", key.Type())
+		}
+
+		val := Eval(entry.Value, env) // 3. 评估值
+		if isAbrupt(val) {
+			return val
+		}
+		hash.Set(hashKey, val)
+	}
+
+	return hash // 4. 返回哈希对象
+}
+
+// hashable 判断对象能否作为哈希键，元组只有在所有元素都能作为哈希键时才可以
+func hashable(obj object.Object) (object.Hashable, bool) {
+	if tuple, ok := obj.(*object.Tuple); ok {
+		for _, e := range tuple.Elements {
+			if _, ok := hashable(e); !ok {
+				return nil, false
+			}
+		}
+	}
+	key, ok := obj.(object.Hashable)
+	return key, ok
+}
+
+// bindNames 评估解构赋值，把元组或数组的元素依次绑定到变量名，元素个数必须与变量个数相同
+func bindNames(names []*ast.Identifier, val object.Object, env *object.Environment) object.Object {
+	var elements []object.Object
+	switch val := val.(type) {
+	case *object.Tuple:
+		elements = val.Elements
+	case *object.Array:
+		elements = val.Elements
+	default:
+		return newError(object.TYPE_ERROR, "cannot unpack %s", val.Type())
+	}
+
+	if len(elements) != len(names) {
+		return newError(object.TYPE_ERROR, "cannot unpack %d values into %d names", len(elements), len(names))
+	}
+	for i, name := range names {
+		env.Set(name.Value, elements[i])
+	}
+	return NULL // 声明语句没有值
+}
+
+// evalIndexExpression 根据对象类型按下标访问，字符串按字符而不是字节计数；负数下标与切片相同，从末尾计数；
+// 哈希中不存在的键得到 nil
+func evalIndexExpression(left, index object.Object) object.Object {
+	if method, ok := lookupMethod(left, "__index__"); ok { // 用户类型通过 __index__ 重载下标访问
+		return applyFunction(method, []object.Object{left, index}, nil)
+	}
+
+	if hash, ok := left.(*object.Hash); ok { // 0. 哈希按键访问
+		key, ok := hashable(index)
+		if !ok {
+			return newError(object.TYPE_ERROR, "unusable as hash key: %s", index.Type())
+		}
+		if val, ok := hash.Get(key); ok {
+			return val
+		}
+		return NULL
+	}
+
+	i, ok := index.(*object.Integer)
+	if !ok {
+		return newError(object.TYPE_ERROR, "index operator not supported: %s[%s]", left.Type(), index.Type())
+	}
+
+	var length int64
+	var at func(i int64) object.Object
+	switch left := left.(type) { // 1. 根据对象类型确定长度和取值方式
+	case *object.Array:
+		length = int64(len(left.Elements))
+		at = func(i int64) object.Object { return left.Elements[i] }
+	case *object.Tuple:
+		length = int64(len(left.Elements))
+		at = func(i int64) object.Object { return left.Elements[i] }
+	case *object.String:
+		chars := []rune(left.Value)
+		length = int64(len(chars))
+		at = func(i int64) object.Object { return &object.Char{Value: chars[i]} }
+	case *object.Bytes:
+		length = int64(len(left.Value))
+		at = func(i int64) object.Object { return &object.Integer{Value: int64(left.Value[i])} }
+	case *object.Range:
+		length = left.Len()
+		at = func(i int64) object.Object { return &object.Integer{Value: left.At(i)} }
+	default:
+		return newError(object.TYPE_ERROR, "index operator not supported: %s[%s]", left.Type(), index.Type())
+	}
+
+	pos := i.Value
+	if pos < 0 { // 2. 负数下标从末尾计数，-1 是最后一个元素
+		pos += length
+	}
+	if pos < 0 || pos >= length { // 3. 下标越界
+		return newError(object.INDEX_ERROR, "index out of range: %d with length %d", i.Value, length)
+	}
+	return at(pos) // 4. 返回该位置的元素
+}
+
+// evalSliceExpression 评估切片表达式，规则与 Python 相同：负数下标从末尾计数，越界的下标被截断，步长为负时倒序截取。
+// 数组切片总是复制元素，得到的新数组与原数组互不影响；字符串切片按字符计数
+func evalSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env) // 1. 评估被切片的对象
+	if isAbrupt(left) {
+		return left
+	}
+	if node.Optional && left == NULL { // 2. "?[" 切片 nil 时结果为 nil
+		return NULL
+	}
+
+	var length int
+	var chars []rune
+	switch left := left.(type) { // 3. 只有数组、元组和字符串可以切片
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.Tuple:
+		length = len(left.Elements)
+	case *object.String:
+		chars = []rune(left.Value)
+		length = len(chars)
+	case *object.Bytes:
+		length = len(left.Value)
+	default:
+		return newError(object.TYPE_ERROR, "slice operator not supported: %s", left.Type())
+	}
+
+	bounds := [3]*int64{} // 4. 依次评估起始下标、结束下标和步长，省略的部分为 nil
+	for i, e := range []ast.Expression{node.Start, node.End, node.Step} {
+		if e == nil {
+			continue
+		}
+		val := Eval(e, env)
+		if isAbrupt(val) {
+			return val
+		}
+		n, ok := val.(*object.Integer)
+		if !ok {
+			return newError(object.TYPE_ERROR, "slice indices must be INTEGER, got %s", val.Type())
+		}
+		bounds[i] = &n.Value
+	}
+
+	indices, err := sliceIndices(length, bounds[0], bounds[1], bounds[2]) // 5. 计算被选中的下标
+	if err != nil {
+		return err
+	}
+
+	switch left := left.(type) { // 6. 按下标复制元素
+	case *object.Array:
+		elements := make([]object.Object, len(indices))
+		for i, idx := range indices {
+			elements[i] = left.Elements[idx]
+		}
+		return &object.Array{Elements: elements}
+	case *object.Tuple:
+		elements := make([]object.Object, len(indices))
+		for i, idx := range indices {
+			elements[i] = left.Elements[idx]
+		}
+		return &object.Tuple{Elements: elements}
+	case *object.Bytes:
+		value := make([]byte, len(indices))
+		for i, idx := range indices {
+			value[i] = left.Value[idx]
+		}
+		return &object.Bytes{Value: value}
+	default:
+		var out strings.Builder
+		for _, idx := range indices {
+			out.WriteRune(chars[idx])
+		}
+		return &object.String{Value: out.String()}
+	}
+}
+
+// sliceIndices 根据长度、起止下标和步长计算切片选中的下标，省略的参数为 nil
+func sliceIndices(length int, start, end, step *int64) ([]int, *object.Error) {
+	s := int64(1)
+	if step != nil {
+		if *step == 0 {
+			return nil, newError(object.ARGUMENT_ERROR, "slice step cannot be zero")
+		}
+		s = *step
+	}
+
+	n := int64(length)
+	lower, upper := int64(0), n // 1. 步长为正时下标范围是 [0, n]，为负时是 [-1, n-1]
+	if s < 0 {
+		lower, upper = -1, n-1
+	}
+	clamp := func(p *int64, dflt int64) int64 {
+		if p == nil {
+			return dflt // 1.1. 省略的下标取默认值
+		}
+		v := *p
+		if v < 0 {
+			v += n // 1.2. 负数下标从末尾计数
+		}
+		return min(max(v, lower), upper) // 1.3. 越界的下标被截断
+	}
+
+	var from, to int64
+	if s > 0 {
+		from, to = clamp(start, lower), clamp(end, upper)
+	} else {
+		from, to = clamp(start, upper), clamp(end, lower)
+	}
+
+	indices := []int{}
+	for i := from; (s > 0 && i < to) || (s < 0 && i > to); i += s { // 2. 按步长收集下标
+		indices = append(indices, int(i))
+	}
+	return indices, nil
+}
+
+// evalInExpression 评估成员测试 "x in y"：区间判断整数是否是其元素，数组比较元素是否相等，哈希判断键是否存在，字符串判断是否包含子串
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Range:
+		n, ok := left.(*object.Integer)
+		return nativeBoolToBooleanObject(ok && right.Contains(n.Value)) // 1. 非整数不会是区间的元素
+	case *object.Array:
+		for _, e := range right.Elements {
+			if objectsEqual(left, e) {
+				return TRUE // 2. 数组中存在相等的元素
+			}
+		}
+		return FALSE
+	case *object.Tuple:
+		for _, e := range right.Elements {
+			if objectsEqual(left, e) {
+				return TRUE // 2.0.1. 元组与数组相同
+			}
+		}
+		return FALSE
+	case *object.Set:
+		elem, ok := hashable(left)
+		return nativeBoolToBooleanObject(ok && right.Has(elem)) // 2.0.2. 不能作为键的对象不会在集合中
+	case *object.Hash:
+		key, ok := hashable(left)
+		if !ok {
+			return FALSE // 2.1. 不能作为键的对象不会在哈希中
+		}
+		_, found := right.Get(key)
+		return nativeBoolToBooleanObject(found)
+	case *object.String:
+		switch sub := left.(type) {
+		case *object.String:
+			return nativeBoolToBooleanObject(strings.Contains(right.Value, sub.Value)) // 3. 判断是否包含子串
+		case *object.Char:
+			return nativeBoolToBooleanObject(strings.ContainsRune(right.Value, sub.Value)) // 3.1. 判断是否包含字符
+		default:
+			return newError(object.TYPE_ERROR, "left operand of in must be STRING or CHAR, got %s", left.Type())
+		}
+	case *object.Bytes:
+		switch sub := left.(type) {
+		case *object.Bytes:
+			return nativeBoolToBooleanObject(bytes.Contains(right.Value, sub.Value)) // 3.2. 判断是否包含子串
+		case *object.Integer:
+			return nativeBoolToBooleanObject(sub.Value >= 0 && sub.Value < 256 && bytes.IndexByte(right.Value, byte(sub.Value)) >= 0) // 3.3. 判断是否包含字节
+		default:
+			return newError(object.TYPE_ERROR, "left operand of in must be BYTES or INTEGER, got %s", left.Type())
+		}
+	default:
+		return newError(object.TYPE_ERROR, "operator in not supported: %s in %s", left.Type(), right.Type())
+	}
+}
+
+// iterate 依次把对象中的元素交给 fn，fn 返回错误或返回值时停止遍历并返回该对象。
+// 支持区间、数组、哈希（按插入顺序遍历键）、字符串（按字符）和生成器
+func iterate(obj object.Object, fn func(object.Object) object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.Range:
+		for i := int64(0); i < obj.Len(); i++ { // 1. 区间按需计算每个元素
+			if result := fn(&object.Integer{Value: obj.At(i)}); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Array:
+		for _, e := range obj.Elements { // 2. 数组依次遍历元素
+			if result := fn(e); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Tuple:
+		for _, e := range obj.Elements { // 2.0.1. 元组与数组相同
+			if result := fn(e); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Hash:
+		for _, k := range obj.Keys { // 2.1. 哈希按插入顺序遍历键
+			if result := fn(obj.Pairs[k].Key); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Set:
+		for _, k := range obj.Keys { // 2.2. 集合按加入顺序遍历元素
+			if result := fn(obj.Members[k]); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.String:
+		for _, ch := range obj.Value { // 3. 字符串按字符遍历
+			if result := fn(&object.Char{Value: ch}); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Bytes:
+		for _, b := range obj.Value { // 3.1. 字节串按字节遍历
+			if result := fn(&object.Integer{Value: int64(b)}); isAbrupt(result) {
+				return result
+			}
+		}
+	case *object.Generator:
+		for { // 4. 生成器一直遍历到 StopIteration
+			val := nextGenerator(obj)
+			if errObj, ok := val.(*object.Error); ok {
+				if errObj.Kind == object.STOP_ITERATION {
+					break
+				}
+				return errObj
+			}
+			if result := fn(val); isAbrupt(result) { // 4.1. 提前结束遍历时关闭生成器，函数体中的 defer 随之执行
+				closeGenerator(obj)
+				return result
+			}
+		}
+	default:
+		return newError(object.TYPE_ERROR, "%s is not iterable", obj.Type())
+	}
+	return nil
+}
+
+// evalForStatement 评估 for 循环，每次迭代都在新的环境中绑定循环变量，因此闭包捕获的是当次迭代的值
+func evalForStatement(node *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env) // 1. 评估被遍历的对象
+	if isAbrupt(iterable) {
+		return iterable
+	}
+
+	result := iterate(iterable, func(item object.Object) object.Object { // 2. 依次执行循环体
+		loopEnv := object.NewEnvironment(env)
+		loopEnv.Set(node.Variable.Value, item)
+		return Eval(node.Body, loopEnv)
+	})
+	if result != nil { // 3. 循环体中的错误或返回值交给外层处理
+		return result
+	}
+
+	return NULL // 4. for 循环本身没有值
+}
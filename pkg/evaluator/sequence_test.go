@@ -0,0 +1,52 @@
+package evaluator
+
+import "testing"
+
+func TestStringIterationYieldsChars(t *testing.T) {
+	expectInspect(t, `[c == 'a' for c in "ab"]`, "[true, false]")
+	expectInspect(t, `"ab"[0] == [c for c in "ab"][0]`, "true")
+	expectInspect(t, `[c for c in "hé"]`, "['h', 'é']")
+}
+
+func TestInterpolatedChars(t *testing.T) {
+	expectInspect(t, `"a ${ '}' } b"`, "a } b")
+	expectInspect(t, `"${ "ab"[1] }!"`, "b!")
+}
+
+func TestNegativeIndexMatchesSlicing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[1, 2, 3][-1]`, "3"},
+		{`[1, 2, 3][-3]`, "1"},
+		{`let xs = [1, 2, 3]; [xs[-2], xs[-2:][0]]`, "[2, 2]"},
+		{`"héllo"[-4]`, "'é'"},
+		{`(1, "a")[-1]`, "a"},
+		{`bytes("ab")[-1]`, "98"},
+		{`(0..10)[-1]`, "9"},
+		{`[1, 2, 3][-4]`, "ERROR: index out of range: -4 with length 3"},
+		{`[][-1]`, "ERROR: index out of range: -1 with length 0"},
+		{`[1, 2, 3][3]`, "ERROR: index out of range: 3 with length 3"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestHashItems(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`items({"a": 1, "b": 2})`, `[("a", 1), ("b", 2)]`},
+		{`items({})`, "[]"},
+		{`let h = {"a": 1, "b": 2}; {k: v * 10 for (k, v) in items(h)}`, `{"a": 10, "b": 20}`},
+		{`let h = {"a": 1, "b": 2}; {v: k for (k, v) in items(h) if v > 1}`, `{2: "b"}`},
+		{`let h = {"a": 1}; [k for k in h]`, `["a"]`},
+		{`items([1])`, "ERROR: argument to items must be HASH, got ARRAY"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
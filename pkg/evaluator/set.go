@@ -0,0 +1,106 @@
+package evaluator
+
+import (
+	"punyGo/pkg/object"
+)
+
+// newSet 由元素列表创建集合，元素必须可以作为哈希键
+func newSet(elements []object.Object) object.Object {
+	set := object.NewSet()
+	for _, e := range elements {
+		elem, ok := hashable(e)
+		if !ok {
+			return newError(object.TYPE_ERROR, "unusable as set element: %s", e.Type())
+		}
+		set.Add(elem)
+	}
+	return set
+}
+
+// toSet 将可遍历的对象转换为集合，集合本身原样返回
+func toSet(obj object.Object) object.Object {
+	if set, ok := obj.(*object.Set); ok {
+		return set
+	}
+	elements := []object.Object{}
+	if result := iterate(obj, func(e object.Object) object.Object {
+		elements = append(elements, e)
+		return nil
+	}); result != nil {
+		return result
+	}
+	return newSet(elements)
+}
+
+// setOperations 记录集合运算的操作符及其方法名
+var setOperations = map[string]string{
+	"|": "union",
+	"&": "intersection",
+	"-": "difference",
+	"^": "symmetric_difference",
+}
+
+// evalSetInfixExpression 评估集合的中缀表达式，支持并集、交集、差集、对称差和相等比较
+func evalSetInfixExpression(operator string, left, right *object.Set) object.Object {
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(objectsEqual(left, right)) // 1. 比较是否相等
+	case "!=":
+		return nativeBoolToBooleanObject(!objectsEqual(left, right)) // 2. 比较是否不等
+	}
+	if name, ok := setOperations[operator]; ok {
+		return combineSets(name, left, right) // 3. 集合运算
+	}
+	return newError(object.OPERATOR_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+// combineSets 按方法名计算两个集合的运算结果。结果先按左侧集合的顺序、再按右侧集合的顺序排列元素，因此输出是确定的
+func combineSets(name string, left, right *object.Set) *object.Set {
+	result := object.NewSet()
+	add := func(from, other *object.Set, wantInOther bool) {
+		for _, k := range from.Keys {
+			if _, found := other.Members[k]; found == wantInOther {
+				result.Add(from.Members[k].(object.Hashable))
+			}
+		}
+	}
+
+	switch name {
+	case "union":
+		for _, k := range left.Keys { // 1. 左侧的所有元素
+			result.Add(left.Members[k].(object.Hashable))
+		}
+		add(right, left, false) // 1.1. 只在右侧的元素
+	case "intersection":
+		add(left, right, true) // 2. 两侧都有的元素
+	case "difference":
+		add(left, right, false) // 3. 只在左侧的元素
+	case "symmetric_difference":
+		add(left, right, false) // 4. 只在一侧的元素
+		add(right, left, false)
+	}
+	return result
+}
+
+// setMethod 返回集合运算的方法形式，例如 s.union(t)，实参可以是任何可遍历的对象；其他名字返回 nil
+func setMethod(set *object.Set, name string) *object.Builtin {
+	for _, op := range setOperations {
+		if op != name {
+			continue
+		}
+		return &object.Builtin{
+			Name: name,
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError(object.ARGUMENT_ERROR, "wrong number of arguments for %s: want=1, got=%d", name, len(args))
+				}
+				other := toSet(args[0])
+				if isAbrupt(other) {
+					return other
+				}
+				return combineSets(name, set, other.(*object.Set))
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"punyGo/pkg/ast"
+	"punyGo/pkg/object"
+)
+
+// tailCall 表示一次尚未执行的尾调用。它只在 evalTail 和 applyFunction 之间传递，
+// applyFunction 在同一层循环中执行它，因此尾递归不会让 Go 栈增长
+type tailCall struct {
+	fn       *object.Function
+	args     []object.Object
+	kwargs   []keywordArg
+	returned bool // 调用来自 return 语句，处于语句块中间时也会结束函数
+}
+
+// Type 方法返回对象的类型
+func (tc *tailCall) Type() object.ObjectType {
+	return "TAIL_CALL"
+}
+
+// Inspect 方法返回尾调用的字符串表示
+func (tc *tailCall) Inspect() string {
+	return "tail call to " + functionName(tc.fn)
+}
+
+// evalTail 评估处于尾位置的节点，即函数体本身以及其中直接决定返回值的部分：
+// 语句块的最后一条语句、任意位置的 return 语句以及 if 和 match 表达式的分支。
+// 语句块中间的 if 和 match 语句也按尾位置评估，使其中的 return f(...) 同样不会让 Go 栈增长。
+// 尾位置上对普通函数的调用不会执行，而是返回 tailCall 交给 applyFunction；其他节点交给 Eval
+func evalTail(node ast.Node, env *object.Environment) object.Object {
+	switch node := node.(type) {
+	case *ast.BlockStatement:
+		var result object.Object = NULL
+		for i, stmt := range node.Statements {
+			if _, ok := stmt.(*ast.ReturnStatement); ok || i == len(node.Statements)-1 {
+				return evalTail(stmt, env) // 1. return 语句和最后一条语句处于尾位置
+			}
+			result = evalStatement(stmt, env)
+			if call, ok := result.(*tailCall); ok && call.returned { // 1.1. 语句块中间的 if 语句通过 return 发起的尾调用
+				return call
+			}
+			if isAbrupt(result) {
+				return result
+			}
+		}
+		return result
+
+	case *ast.ExpressionStatement:
+		return evalTail(node.Expression, env) // 2. 表达式语句的值就是语句块的值
+
+	case *ast.ReturnStatement:
+		val := evalTail(node.ReturnValue, env) // 3. return 的值处于尾位置
+		if call, ok := val.(*tailCall); ok {
+			call.returned = true
+			return call
+		}
+		if isAbrupt(val) {
+			return val
+		}
+		return &object.ReturnValue{Value: val}
+
+	case *ast.IfExpression:
+		branch, cond := ifBranch(node, env) // 4. 条件不处于尾位置，分支处于尾位置
+		if cond != nil {
+			return cond
+		}
+		if branch == nil {
+			return NULL
+		}
+		return evalTail(branch, env)
+
+	case *ast.MatchExpression:
+		branch, branchEnv, err := matchBranch(node, env) // 4.1. 被匹配的值不处于尾位置，分支处于尾位置
+		if err != nil {
+			return err
+		}
+		return evalTail(branch, branchEnv)
+
+	case *ast.CallExpression:
+		if name := node.Function.TokenLiteral(); name == "quote" || name == "recover" { // 5. 特殊形式交给 Eval
+			return Eval(node, env)
+		}
+		function := Eval(node.Function, env) // 6. 评估被调用对象和实参
+		if isAbrupt(function) {
+			return function
+		}
+		args, kwargs, abrupt := evalCallArguments(node.Arguments, env)
+		if abrupt != nil {
+			return abrupt
+		}
+		if fn, ok := function.(*object.Function); ok && !fn.IsGenerator { // 7. 推迟对普通函数的调用
+			return &tailCall{fn: fn, args: args, kwargs: kwargs}
+		}
+		return applyFunction(function, args, kwargs) // 8. 其他可调用对象直接执行
+
+	default:
+		return Eval(node, env)
+	}
+}
+
+// evalStatement 评估语句块中间的语句。if 和 match 语句按尾位置评估，其中由 return 发起的尾调用原样返回，
+// 分支最后一个表达式中的调用不决定函数的返回值，立即执行
+func evalStatement(stmt ast.Statement, env *object.Environment) object.Object {
+	es, ok := stmt.(*ast.ExpressionStatement)
+	if !ok {
+		return Eval(stmt, env)
+	}
+	switch es.Expression.(type) {
+	case *ast.IfExpression, *ast.MatchExpression:
+	default:
+		return Eval(stmt, env)
+	}
+	result := evalTail(es.Expression, env)
+	if call, ok := result.(*tailCall); ok && !call.returned {
+		return callFunction(call.fn, call.args, call.kwargs, nil)
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package evaluator
+
+import "testing"
+
+func TestTailCallIfElse(t *testing.T) {
+	expectInspect(t, `
+let count = fn(n, acc) { if (n == 0) { acc } else { count(n - 1, acc + 1) } };
+count(1000000, 0)`, "1000000")
+}
+
+func TestTailCallEarlyReturn(t *testing.T) {
+	expectInspect(t, `
+let loop = fn(n) { if (n > 0) { return loop(n - 1); } 0 };
+loop(1000000)`, "0")
+}
+
+func TestTailCallMutualRecursion(t *testing.T) {
+	expectInspect(t, `
+let even = fn(n) { if (n == 0) { return true; } odd(n - 1) };
+let odd = fn(n) { if (n == 0) { return false; } even(n - 1) };
+even(1000001)`, "false")
+}
+
+func TestNonFinalIfCallRunsImmediately(t *testing.T) {
+	expectInspect(t, `
+let g = fn() { 3 };
+let h = fn(n) { if (n > 0) { g() } 8 };
+h(1)`, "8")
+}
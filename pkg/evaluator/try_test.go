@@ -0,0 +1,9 @@
+package evaluator
+
+import "testing"
+
+func TestDivisionByZeroIsCatchable(t *testing.T) {
+	expectInspect(t, `try { 1 / 0 } catch (e) { e.kind }`, "OperatorError")
+	expectInspect(t, `try { 1 / 0 } catch (e) { e.message }`, "integer division by zero")
+	expectInspect(t, `try { 1 % 0 } catch (e) { e.message }`, "integer modulo by zero")
+}
@@ -0,0 +1,38 @@
+package evaluator
+
+import "testing"
+
+func TestTuples(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`(1, "a", true)`, `(1, "a", true)`},
+		{`(1)`, "1"},
+		{`(1,)`, "(1,)"},
+		{`()`, "()"},
+		{`[(1, 2) == (1, 2), (1, 2) == (2, 1)]`, "[true, false]"},
+		{`let t = (1, 2); [t[0], t[-1], len(t)]`, "[1, 2, 2]"},
+		{`let h = {(1, 2): "x"}; h[(1, 2)]`, "x"},
+		{`{(1, [2]): "x"}`, "ERROR: unusable as hash key: TUPLE"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestMultipleReturnValues(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let (q, r) = divmod(7, 2); [q, r]`, "[3, 1]"},
+		{`let f = fn() { (1, 2) }; let (a, b) = f(); a + b`, "3"},
+		{`let (a, _) = (1, 2); a`, "1"},
+		{`let (a, b) = [1, 2]; b`, "2"},
+		{`let (a, b) = (1, 2, 3); a`, "ERROR: cannot unpack 3 values into 2 names"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,42 @@
+package evaluator
+
+import "testing"
+
+func TestVariadicParameters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let sum = fn(...nums) { let total = fn(xs, i, acc) { if (i == len(xs)) { acc } else { total(xs, i + 1, acc + xs[i]) } }; total(nums, 0, 0) }; [sum(), sum(1, 2, 3)]`, "[0, 6]"},
+		{`let f = fn(a, ...rest) { [a, rest] }; [f(1), f(1, 2, 3)]`, "[[1, []], [1, [2, 3]]]"},
+		{`let f = fn(a, ...r) { r }; f(a: 1)`, "[]"},
+		{`let f = fn(a, b) { a + b }; f(1)`, "ERROR: wrong number of arguments for f: want=2, got=1"},
+		{`let f = fn(a, b) { a + b }; f(1, 2, 3)`, "ERROR: wrong number of arguments for f: want=2, got=3"},
+		{`let f = fn(a, ...r) { a }; f()`, "ERROR: wrong number of arguments for f: want>=1, got=0"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let f = fn(a, b, c) { a + b + c }; let args = [1, 2, 3]; f(...args)`, "6"},
+		{`let f = fn(a, b, c) { a + b + c }; f(1, ...[2, 3])`, "6"},
+		{`let f = fn(a, b = 10) { a + b }; [f(1), f(...[1, 2])]`, "[11, 3]"},
+		{`let f = fn(a, b) { a + b }; f(...[1, 2, 3])`, "ERROR: wrong number of arguments for f: want=2, got=3"},
+		{`let a = [1, 2]; let b = [3]; [...a, ...b, 4]`, "[1, 2, 3, 4]"},
+		{`[...(1, 2), ...0..3]`, "[1, 2, 0, 1, 2]"},
+		{`(1, ...[2, 3])`, "(1, 2, 3)"},
+		{`let m = {"a": 1, "b": 2}; {...m, "b": 3, "c": 4}`, `{"a": 1, "b": 3, "c": 4}`},
+		{`[...5]`, "ERROR: INTEGER is not iterable"},
+		{`let f = fn(a) { a }; f(...5)`, "ERROR: INTEGER is not iterable"},
+		{`{...[1, 2]}`, "ERROR: cannot spread ARRAY into a hash"},
+	}
+	for _, tt := range tests {
+		expectInspect(t, tt.input, tt.want)
+	}
+}
@@ -1,40 +1,82 @@
 package lexer
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"punyGo/pkg/token"
 )
 
-// Lexer 结构体定义了词法分析器的状态
+// Lexer 结构体定义了词法分析器的状态，输入按 UTF-8 解码为字符（rune）
 type Lexer struct {
 	input        string // 输入的源代码
-	position     int    // 当前字符的位置（当前读取的字符）
-	readPosition int    // 下一个字符的位置（即将读取的字符）
-	ch           byte   // 当前读取的字符
+	position     int    // 当前字符的字节位置（当前读取的字符）
+	readPosition int    // 下一个字符的字节位置（即将读取的字符）
+	ch           rune   // 当前读取的字符
+	line         int    // 当前字符所在的行，从 1 开始
+	column       int    // 当前字符所在的列，按字符而不是字节计数，从 1 开始
+	keepComments bool   // 是否把注释作为 COMMENT Token 返回
 }
 
 // New 创建并返回一个新的 Lexer 实例
 func New(input string) *Lexer {
-	l := &Lexer{input: input} // 1. 初始化 Lexer 结构体，设置输入源代码
-	l.readChar()              // 2. 读取第一个字符，初始化 ch、position 和 readPosition
-	return l                  // 3. 返回初始化后的 Lexer 实例
+	return NewAt(input, 1, 1)
+}
+
+// NewAt 创建一个从指定行列开始计数的 Lexer 实例，用于解析嵌入在其他源代码中的片段
+func NewAt(input string, line, column int) *Lexer {
+	l := &Lexer{input: input, line: line, column: column - 1} // 1. 初始化 Lexer 结构体，设置输入源代码和起始位置
+	l.readChar()                                              // 2. 读取第一个字符，初始化 ch、position 和 readPosition
+	return l                                                  // 3. 返回初始化后的 Lexer 实例
+}
+
+// NewWithComments 创建一个保留注释的 Lexer 实例，注释会作为 COMMENT Token 返回，供格式化等工具使用
+func NewWithComments(input string) *Lexer {
+	l := New(input)
+	l.keepComments = true
+	return l
 }
 
 // readChar 读取下一个字符并更新 Lexer 的状态
 func (l *Lexer) readChar() {
-	if l.readPosition >= len(l.input) { // 1. 判断是否已读取到输入的末尾
-		l.ch = 0 // 2. 如果是，设置当前字符为 0，表示文件结束（EOF）
+	if l.ch == '\n' { // 1. 离开换行符时进入下一行
+		l.line++
+		l.column = 0
+	}
+
+	width := 0
+	if l.readPosition >= len(l.input) { // 2. 判断是否已读取到输入的末尾
+		l.ch = 0 // 3. 如果是，设置当前字符为 0，表示文件结束（EOF）
 	} else {
-		l.ch = l.input[l.readPosition] // 3. 否则，读取下一个字符
+		l.ch, width = utf8.DecodeRuneInString(l.input[l.readPosition:]) // 4. 否则，解码下一个字符
 	}
-	l.position = l.readPosition // 4. 更新当前字符的位置
-	l.readPosition++            // 5. 更新下一个字符的位置
+	l.position = l.readPosition // 5. 更新当前字符的位置
+	l.readPosition += width     // 6. 更新下一个字符的位置
+	l.column++                  // 7. 列号按字符递增
 }
 
 // NextToken 解析输入并返回下一个 Token
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
-	l.skipWhitespace() // 1. 跳过所有空白字符
+	for { // 1. 跳过所有空白字符和注释
+		l.skipWhitespace()
+		if l.ch != '/' || (l.peekChar() != '/' && l.peekChar() != '*') {
+			break
+		}
+		line, column := l.line, l.column
+		comment := l.readComment() // 1.1. 读取一段注释
+		if comment.Type == token.ILLEGAL || l.keepComments {
+			comment.Line, comment.Column = line, column
+			return comment // 1.2. 未闭合的注释，或者需要保留注释时，返回该Token
+		}
+	}
+
+	line, column := l.line, l.column // 1.3. 记录 Token 的起始位置
+	tok.Line, tok.Column = line, column
 
 	switch l.ch { // 2. 根据当前字符决定 Token 类型
 	case '=':
@@ -43,13 +85,21 @@ func (l *Lexer) NextToken() token.Token {
 			l.readChar()                                        // 2.1.2 读取下一个字符
 			literal := string(ch) + string(l.ch)                // 2.1.3 组合成 "=="
 			tok = token.Token{Type: token.EQ, Literal: literal} // 2.1.4 创建 EQ Token
+		} else if l.peekChar() == '~' { // 2.1.5 如果下一个字符是 '~', 则是正则匹配操作符
+			l.readChar()
+			tok = token.Token{Type: token.REGEX_MATCH, Literal: "=~"}
 		} else {
 			tok = newToken(token.ASSIGN, l.ch) // 2.2 否则，创建赋值操作符 Token
 		}
 	case '+':
 		tok = newToken(token.PLUS, l.ch) // 3. 处理 '+' 操作符
 	case '-':
-		tok = newToken(token.MINUS, l.ch) // 4. 处理 '-' 操作符
+		if l.peekChar() == '>' { // 4.1. 处理返回值类型标注的箭头 "->"
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: "->"}
+		} else {
+			tok = newToken(token.MINUS, l.ch) // 4. 处理 '-' 操作符
+		}
 	case '!':
 		if l.peekChar() == '=' { // 5.1 如果下一个字符是 '=', 则是非等于操作符
 			ch := l.ch                                              // 5.1.1 保存当前字符
@@ -63,6 +113,24 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.SLASH, l.ch) // 6. 处理 '/' 操作符
 	case '*':
 		tok = newToken(token.ASTERISK, l.ch) // 7. 处理 '*' 操作符
+	case '%':
+		tok = newToken(token.PERCENT, l.ch) // 7.0. 处理 '%' 操作符
+	case '|':
+		tok = newToken(token.PIPE, l.ch) // 7.0.1. 处理 '|' 操作符
+	case '&':
+		tok = newToken(token.AMPERSAND, l.ch) // 7.0.2. 处理 '&' 操作符
+	case '^':
+		tok = newToken(token.CARET, l.ch) // 7.0.3. 处理 '^' 操作符
+	case '?':
+		switch l.peekChar() {
+		case '.', '[', '?': // 7.1. 处理 "?."、"?[" 和 "??" 操作符，它们的 Token 类型与字面量相同
+			ch := l.ch
+			l.readChar()
+			literal := string(ch) + string(l.ch)
+			tok = token.Token{Type: token.TokenType(literal), Literal: literal}
+		default:
+			tok = newToken(token.QUESTION, l.ch) // 7.2. 处理 '?' 操作符
+		}
 	case '<':
 		tok = newToken(token.LT, l.ch) // 8. 处理 '<' 操作符
 	case '>':
@@ -71,6 +139,27 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.SEMICOLON, l.ch) // 10. 处理分号 ';'
 	case ',':
 		tok = newToken(token.COMMA, l.ch) // 11. 处理逗号 ','
+	case '.':
+		if l.peekChar() == '.' { // 11.1. 处理区间操作符 ".."、"..=" 和展开操作符 "..."
+			l.readChar()
+			tok = token.Token{Type: token.DOTDOT, Literal: ".."}
+			switch l.peekChar() {
+			case '=':
+				l.readChar()
+				tok = token.Token{Type: token.DOTDOT_EQ, Literal: "..="}
+			case '.':
+				l.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			}
+		} else {
+			tok = newToken(token.DOT, l.ch) // 11.1.1. 否则处理点号 '.'
+		}
+	case '"':
+		tok = l.readString() // 11.2. 处理字符串字面量，读取引号之间的内容
+	case '\'':
+		tok = l.readQuoted(token.CHAR, '\'') // 11.2.2. 处理字符字面量
+	case ':':
+		tok = newToken(token.COLON, l.ch) // 11.3. 处理冒号 ':'
 	case '(':
 		tok = newToken(token.LPAREN, l.ch) // 12. 处理左括号 '('
 	case ')':
@@ -79,11 +168,29 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.LBRACE, l.ch) // 14. 处理左大括号 '{'
 	case '}':
 		tok = newToken(token.RBRACE, l.ch) // 15. 处理右大括号 '}'
+	case '#':
+		if l.peekChar() == '{' { // 15.0.1. 处理集合字面量的起始 "#{"
+			l.readChar()
+			tok = token.Token{Type: token.SET_LBRACE, Literal: "#{"}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
+	case '[':
+		tok = newToken(token.LBRACKET, l.ch) // 15.1. 处理左中括号 '['
+	case ']':
+		tok = newToken(token.RBRACKET, l.ch) // 15.2. 处理右中括号 ']'
 	case 0:
 		tok.Literal = ""     // 16. 如果是 EOF，设置空字符串
 		tok.Type = token.EOF // 17. 设置 Token 类型为 EOF
 	default:
-		if isLetter(l.ch) { // 18. 如果当前字符是字母，读取整个标识符
+		if l.ch == 'b' && l.peekChar() == '"' { // 17.1. b 紧跟双引号是字节串字面量
+			l.readChar()
+			tok = l.readQuoted(token.BYTES, '"')
+		} else if l.ch == 'r' && strings.HasPrefix(l.input[l.readPosition:], "e\"") { // 17.2. re 紧跟双引号是正则表达式字面量
+			l.readChar()
+			l.readChar()
+			tok = l.readRegex()
+		} else if isLetter(l.ch) { // 18. 如果当前字符是字母，读取整个标识符
 			tok.Literal = l.readIdentifier()          // 18.1 读取标识符
 			tok.Type = token.LookupIdent(tok.Literal) // 18.2 确定标识符的 Token 类型
 			return tok                                // 18.3 返回标识符 Token
@@ -96,19 +203,20 @@ func (l *Lexer) NextToken() token.Token {
 		}
 	}
 
-	l.readChar() // 21. 读取下一个字符，为下一次调用做准备
-	return tok   // 22. 返回当前 Token
+	l.readChar()                        // 21. 读取下一个字符，为下一次调用做准备
+	tok.Line, tok.Column = line, column // 22. 设置 Token 的起始位置
+	return tok                          // 23. 返回当前 Token
 }
 
 // newToken 辅助函数，根据类型和字符创建一个新的 Token
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
-// readIdentifier 读取一个标识符，并返回其字符串
+// readIdentifier 读取一个标识符，并返回其字符串；标识符以字母开头，之后可以是字母或数字
 func (l *Lexer) readIdentifier() string {
-	position := l.position // 1. 记录标识符的起始位置
-	for isLetter(l.ch) {   // 2. 当当前字符是字母时，继续读取
+	position := l.position                        // 1. 记录标识符的起始位置
+	for isLetter(l.ch) || unicode.IsDigit(l.ch) { // 2. 当当前字符是字母或数字时，继续读取
 		l.readChar() // 3. 读取下一个字符
 	}
 	return l.input[position:l.position] // 4. 返回标识符的字符串
@@ -123,6 +231,215 @@ func (l *Lexer) readNumber() string {
 	return l.input[position:l.position] // 4. 返回数字的字符串
 }
 
+// readString 读取双引号之间的字符串内容，结束时当前字符为右引号；没有右引号时返回 ILLEGAL。
+// 字符串中的 "${...}" 插值会被完整跳过，因此插值中的大括号和字符串不会提前结束字符串。
+// 与字节串相同，Token 的字面量是未经转义的原始内容，转义序列由 Unescape 解析
+func (l *Lexer) readString() token.Token {
+	position := l.position + 1 // 1. 跳过左引号，记录字符串的起始位置
+	l.skipStringBody()         // 2. 读取到右引号或输入结束
+	if l.ch == 0 {
+		return token.Token{Type: token.ILLEGAL, Literal: "unterminated string literal"}
+	}
+	return token.Token{Type: token.STRING, Literal: l.input[position:l.position]} // 3. 返回引号之间的内容
+}
+
+// skipStringBody 从左引号开始读取字符串内容，直到右引号或输入结束
+func (l *Lexer) skipStringBody() {
+	for {
+		l.readChar()                  // 1. 读取下一个字符
+		if l.ch == '"' || l.ch == 0 { // 2. 遇到右引号或输入结束时停止
+			return
+		}
+		if l.ch == '\\' { // 2.1. 跳过被转义的字符，转义的引号不会结束字符串
+			l.readChar()
+			if l.ch == 0 {
+				return
+			}
+			continue
+		}
+		if l.ch == '$' && l.peekChar() == '{' { // 3. 遇到插值时跳过整个插值表达式
+			l.readChar()
+			l.skipInterpolation()
+			if l.ch == 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipInterpolation 从插值的左大括号开始，读取到与之匹配的右大括号
+func (l *Lexer) skipInterpolation() {
+	depth := 1 // 1. 记录大括号的嵌套深度
+	for depth > 0 {
+		l.readChar()
+		switch l.ch {
+		case 0:
+			return // 2. 输入结束，插值没有闭合
+		case '{':
+			depth++ // 3. 进入嵌套的大括号
+		case '}':
+			depth-- // 4. 离开一层大括号
+		case '"':
+			l.skipStringBody() // 5. 跳过插值表达式中的字符串，它们也可以包含插值
+			if l.ch == 0 {
+				return
+			}
+		case '\'':
+			l.readQuoted(token.CHAR, '\'') // 6. 跳过字符字面量，例如 '}'
+			if l.ch == 0 {
+				return
+			}
+		case '/':
+			if l.peekChar() == '/' || l.peekChar() == '*' { // 7. 跳过注释，注释中的大括号不影响嵌套深度
+				l.skipComment()
+				if l.ch == 0 {
+					return
+				}
+			}
+		}
+	}
+}
+
+// skipComment 从注释的第一个字符开始跳过一段注释，结束时当前字符为注释的最后一个字符，注释没有闭合时为输入结束
+func (l *Lexer) skipComment() {
+	if l.peekChar() == '/' { // 1. 行注释跳过到换行符之前
+		for l.peekChar() != '\n' && l.peekChar() != 0 {
+			l.readChar()
+		}
+		return
+	}
+
+	l.readChar() // 2. 块注释跳过开头的 "/*"，可以嵌套
+	depth := 1
+	for depth > 0 {
+		l.readChar()
+		switch {
+		case l.ch == 0:
+			return
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+		}
+	}
+}
+
+// readQuoted 读取引号之间的字节串或字符字面量，反斜杠转义的引号不会结束字面量，转义序列由 Unescape 解析。
+// 结束时当前字符为右引号；没有右引号时返回 ILLEGAL
+func (l *Lexer) readQuoted(tokenType token.TokenType, quote rune) token.Token {
+	position := l.position + 1 // 1. 跳过左引号
+	for {
+		l.readChar()
+		switch l.ch {
+		case 0, '\n': // 2. 字面量不能跨行
+			return token.Token{Type: token.ILLEGAL, Literal: "unterminated " + strings.ToLower(string(tokenType)) + " literal"}
+		case '\\':
+			l.readChar() // 3. 跳过被转义的字符
+		case quote:
+			return token.Token{Type: tokenType, Literal: l.input[position:l.position]} // 4. 返回引号之间的原始内容
+		}
+	}
+}
+
+// readRegex 读取正则表达式字面量，当前字符为左引号。右引号之后紧跟的字母是标志，例如 re"[a-z]+"i；
+// Token 的字面量是从左引号到最后一个标志的原始内容，结束时当前字符为右引号或最后一个标志
+func (l *Lexer) readRegex() token.Token {
+	position := l.position
+	tok := l.readQuoted(token.REGEX, '"') // 1. 读取引号之间的模式，反斜杠原样保留
+	if tok.Type == token.ILLEGAL {
+		return tok
+	}
+	for isLetter(l.peekChar()) { // 2. 读取标志
+		l.readChar()
+	}
+	tok.Literal = l.input[position : l.position+1]
+	return tok
+}
+
+// Unescape 解析字符串、字节串和字符字面量中的转义序列：\\、\'、\"、\$、\n、\t、\r、\0 以及 \xHH
+func Unescape(s string) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' { // 1. 普通字符原样保留
+			out.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("trailing backslash")
+		}
+		switch s[i] { // 2. 解析转义序列
+		case '\\', '\'', '"', '$':
+			out.WriteByte(s[i])
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '0':
+			out.WriteByte(0)
+		case 'x': // 2.1. 两位十六进制表示一个字节
+			if i+3 > len(s) {
+				return "", fmt.Errorf("\\x must be followed by two hex digits")
+			}
+			b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil || s[i+1] == '+' || s[i+1] == '-' {
+				return "", fmt.Errorf("\\x must be followed by two hex digits")
+			}
+			out.WriteByte(byte(b))
+			i += 2
+		default:
+			return "", fmt.Errorf("unknown escape sequence \\%c", s[i])
+		}
+	}
+	return out.String(), nil
+}
+
+// InterpolationEnd 返回字符串内容 s 中从下标 open 处的左大括号开始的插值所对应的右大括号下标，没有闭合时返回 -1
+func InterpolationEnd(s string, open int) int {
+	l := &Lexer{input: s, readPosition: open} // 1. 从左大括号处开始读取
+	l.readChar()
+	l.skipInterpolation() // 2. 使用与词法分析相同的规则跳过插值
+	if l.ch != '}' {
+		return -1
+	}
+	return l.position // 3. 返回右大括号的下标
+}
+
+// readComment 读取一段注释，"//" 注释到行尾结束，"/* */" 注释可以嵌套；结束时当前字符为注释之后的字符
+func (l *Lexer) readComment() token.Token {
+	position := l.position // 1. 记录注释的起始位置
+
+	if l.peekChar() == '/' { // 2. 行注释读取到换行符或输入结束
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+		return token.Token{Type: token.COMMENT, Literal: l.input[position:l.position]}
+	}
+
+	l.readChar() // 3. 跳过块注释开头的 "/*"
+	l.readChar()
+	depth := 1
+	for depth > 0 {
+		switch {
+		case l.ch == 0: // 3.1. 输入结束时注释仍未闭合
+			return token.Token{Type: token.ILLEGAL, Literal: "unterminated block comment"}
+		case l.ch == '/' && l.peekChar() == '*': // 3.2. 进入嵌套的块注释
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/': // 3.3. 离开一层块注释
+			depth--
+			l.readChar()
+		}
+		l.readChar()
+	}
+
+	return token.Token{Type: token.COMMENT, Literal: l.input[position:l.position]} // 4. 返回整段注释
+}
+
 // skipWhitespace 跳过所有空白字符
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' { // 1. 判断当前字符是否为空白字符
@@ -131,20 +448,21 @@ func (l *Lexer) skipWhitespace() {
 }
 
 // peekChar 查看下一个字符，但不移动位置
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) { // 1. 判断下一个位置是否超出输入长度
 		return 0 // 2. 如果是，返回 0，表示 EOF
 	} else {
-		return l.input[l.readPosition] // 3. 否则，返回下一个字符
+		ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:]) // 3. 否则，解码并返回下一个字符
+		return ch
 	}
 }
 
-// isLetter 判断一个字符是否是字母或下划线
-func isLetter(ch byte) bool {
-	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || ch == '_'
+// isLetter 判断一个字符是否是字母或下划线，字母的范围与 Go 语言规范一致，包含所有 Unicode 字母
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-// isDigit 判断一个字符是否是数字
-func isDigit(ch byte) bool {
+// isDigit 判断一个字符是否是十进制数字，数字字面量只使用 ASCII 数字
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
@@ -0,0 +1,117 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"punyGo/pkg/token"
+)
+
+func TestStringLiterals(t *testing.T) {
+	tests := []struct {
+		input   string
+		typ     token.TokenType
+		literal string
+	}{
+		{`"abc"`, token.STRING, "abc"},
+		{`"a\"b"`, token.STRING, `a\"b`},
+		{`"a ${ "}" } b"`, token.STRING, `a ${ "}" } b`},
+		{"\"abc;\nlet b = 2;", token.ILLEGAL, "unterminated string literal"},
+		{`"abc\"`, token.ILLEGAL, "unterminated string literal"},
+	}
+	for _, tt := range tests {
+		tok := New(tt.input).NextToken()
+		if tok.Type != tt.typ || tok.Literal != tt.literal {
+			t.Errorf("%s: got %s %q, want %s %q", tt.input, tok.Type, tok.Literal, tt.typ, tt.literal)
+		}
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	tests := map[string]string{
+		`a\"b`:   `a"b`,
+		`\n\t`:   "\n\t",
+		`\${x}`:  "${x}",
+		`\x41\\`: `A\`,
+	}
+	for input, want := range tests {
+		got, err := Unescape(input)
+		if err != nil || got != want {
+			t.Errorf("Unescape(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := Unescape(`\q`); err == nil {
+		t.Errorf("Unescape(%q) should fail", `\q`)
+	}
+}
+
+func TestInterpolationSkipsCharsAndComments(t *testing.T) {
+	tests := []string{
+		`"a ${ '}' } b"`,
+		`"a ${ '\'' } b"`,
+		`"a ${ 1 /* } */ } b"`,
+	}
+	for _, input := range tests {
+		tok := New(input).NextToken()
+		if tok.Type != token.STRING || tok.Literal != input[1:len(input)-1] {
+			t.Errorf("%s: got %s %q", input, tok.Type, tok.Literal)
+		}
+	}
+}
+
+// tokens 依次读取 Token 直到 EOF 或非法 Token，返回形如 "类型(字面量)@行:列" 的描述
+func tokens(l *Lexer) []string {
+	out := []string{}
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		out = append(out, fmt.Sprintf("%s(%s)@%d:%d", tok.Type, tok.Literal, tok.Line, tok.Column))
+		if tok.Type == token.ILLEGAL {
+			break
+		}
+	}
+	return out
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // 跳过注释时的 Token
+		kept  string // 保留注释时的 Token
+	}{
+		{"1 // note\n2", "INT(1)@1:1 INT(2)@2:1", "INT(1)@1:1 COMMENT(// note)@1:3 INT(2)@2:1"},
+		{"1 /* a /* b */ c */ 2", "INT(1)@1:1 INT(2)@1:21", "INT(1)@1:1 COMMENT(/* a /* b */ c */)@1:3 INT(2)@1:21"},
+		{"a /* x */ / b", "IDENT(a)@1:1 /(/)@1:11 IDENT(b)@1:13", "IDENT(a)@1:1 COMMENT(/* x */)@1:3 /(/)@1:11 IDENT(b)@1:13"},
+		{"1 //", "INT(1)@1:1", "INT(1)@1:1 COMMENT(//)@1:3"},
+		{"/* x */", "", "COMMENT(/* x */)@1:1"},
+		{"1 /* open", "INT(1)@1:1 ILLEGAL(unterminated block comment)@1:3", "INT(1)@1:1 ILLEGAL(unterminated block comment)@1:3"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tokens(New(tt.input)), " "); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.input, got, tt.want)
+		}
+		if got := strings.Join(tokens(NewWithComments(tt.input)), " "); got != tt.kept {
+			t.Errorf("%q with comments: got %s, want %s", tt.input, got, tt.kept)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let 名字 = "你好"; 名字`, "LET(let)@1:1 IDENT(名字)@1:5 =(=)@1:8 STRING(你好)@1:10 ;(;)@1:14 IDENT(名字)@1:16"},
+		{"é1 + ß", "IDENT(é1)@1:1 +(+)@1:4 IDENT(ß)@1:6"},
+		{"_a1 a_1 Ωmega", "IDENT(_a1)@1:1 IDENT(a_1)@1:5 IDENT(Ωmega)@1:9"},
+		{"'中' y", "CHAR(中)@1:1 IDENT(y)@1:5"},
+		{"é\n  ü", "IDENT(é)@1:1 IDENT(ü)@2:3"},
+		{"x٣ ٣", "IDENT(x٣)@1:1 ILLEGAL(٣)@1:4"}, // Unicode 数字可以出现在标识符中，但不能开头
+		{"x²", "IDENT(x)@1:1 ILLEGAL(²)@1:2"},    // 上标不是数字
+		{"1 ＋ 2", "INT(1)@1:1 ILLEGAL(＋)@1:3"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tokens(New(tt.input)), " "); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,19 @@
 
 package object
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/token"
+)
 
 // 定义对象类型的别名为字符串
 type ObjectType string
@@ -12,6 +24,47 @@ const (
 	INTEGER_OBJ      = "INTEGER"      // 整数对象
 	RETURN_VALUE_OBJ = "RETURN_VALUE" // 返回值对象
 	ERROR_OBJ        = "ERROR"        // 错误对象
+	BOOLEAN_OBJ      = "BOOLEAN"      // 布尔对象
+	ENUM_OBJ         = "ENUM"         // 枚举类型对象
+	VARIANT_OBJ      = "VARIANT"      // 枚举变体构造函数对象
+	ENUM_VALUE_OBJ   = "ENUM_VALUE"   // 枚举值对象
+	ERROR_VALUE_OBJ  = "ERROR_VALUE"  // 被 catch 捕获的错误值对象
+	FUNCTION_OBJ     = "FUNCTION"     // 函数对象
+	BUILTIN_OBJ      = "BUILTIN"      // 内置函数对象
+	GENERATOR_OBJ    = "GENERATOR"    // 生成器对象
+	TASK_OBJ         = "TASK"         // spawn 创建的并发任务对象
+	CHANNEL_OBJ      = "CHANNEL"      // 通道对象
+	STRING_OBJ       = "STRING"       // 字符串对象
+	MODULE_OBJ       = "MODULE"       // 模块对象
+	QUOTE_OBJ        = "QUOTE"        // quote 得到的 AST 对象
+	MACRO_OBJ        = "MACRO"        // 宏对象
+	NULL_OBJ         = "NULL"         // 空值对象
+	ARRAY_OBJ        = "ARRAY"        // 数组对象
+	RANGE_OBJ        = "RANGE"        // 区间对象
+	HASH_OBJ         = "HASH"         // 哈希对象
+	TUPLE_OBJ        = "TUPLE"        // 元组对象
+	SET_OBJ          = "SET"          // 集合对象
+	BYTES_OBJ        = "BYTES"        // 字节串对象
+	CHAR_OBJ         = "CHAR"         // 字符对象
+	REGEX_OBJ        = "REGEX"        // 正则表达式对象
+)
+
+// 错误种类，用于区分运行时错误的来源
+const (
+	TYPE_ERROR     = "TypeError"     // 类型不匹配或对象不支持该操作
+	NAME_ERROR     = "NameError"     // 标识符未定义
+	OPERATOR_ERROR = "OperatorError" // 未知操作符
+	ARGUMENT_ERROR = "ArgumentError" // 实参数量错误
+	STOP_ITERATION = "StopIteration" // 生成器已经结束
+	GENERATOR_EXIT = "GeneratorExit" // 生成器被调用方关闭，用于结束暂停中的函数体
+	INDEX_ERROR    = "IndexError"    // 下标越界
+	CHANNEL_ERROR  = "ChannelError"  // 通道已关闭时的收发或重复关闭
+	IMPORT_ERROR   = "ImportError"   // 模块找不到、无法读取或循环导入
+	SYNTAX_ERROR   = "SyntaxError"   // 被导入的模块存在语法错误
+	MACRO_ERROR    = "MacroError"    // 宏展开失败
+	REGEX_ERROR    = "RegexError"    // 正则表达式无法编译
+	MATCH_ERROR    = "MatchError"    // match 表达式没有匹配的分支
+	THROWN_ERROR   = "Error"         // 由 throw 抛出的错误
 )
 
 // Object 接口定义了所有对象必须实现的方法
@@ -35,9 +88,438 @@ func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%d", i.Value)
 }
 
-// Error 结构体表示错误对象
+// HashKey 方法返回整数作为哈希键时的键值
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+// Boolean 结构体表示布尔对象
+type Boolean struct {
+	Value bool // 布尔值
+}
+
+// Type 方法返回对象的类型
+func (b *Boolean) Type() ObjectType {
+	return BOOLEAN_OBJ
+}
+
+// Inspect 方法返回布尔值的字符串表示
+func (b *Boolean) Inspect() string {
+	return fmt.Sprintf("%t", b.Value)
+}
+
+// HashKey 方法返回布尔值作为哈希键时的键值
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+// Null 结构体表示空值，没有值的语句和缺失的数据都求值为它
+type Null struct{}
+
+// Type 方法返回对象的类型
+func (n *Null) Type() ObjectType {
+	return NULL_OBJ
+}
+
+// Inspect 方法返回空值的字符串表示
+func (n *Null) Inspect() string {
+	return "nil"
+}
+
+// Array 结构体表示数组对象。切片会复制元素，得到的新数组与原数组互不影响
+type Array struct {
+	Elements []Object // 数组元素
+}
+
+// Type 方法返回对象的类型
+func (a *Array) Type() ObjectType {
+	return ARRAY_OBJ
+}
+
+// Inspect 方法返回数组的字符串表示，例如 [1, 2, "a"]，字符串元素带引号
+func (a *Array) Inspect() string {
+	elements := []string{}
+	for _, e := range a.Elements {
+		elements = append(elements, inspectElement(e))
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Set 结构体表示集合对象，元素与哈希的键遵循相同的规则。遍历时按元素第一次加入的顺序进行，
+// 输出时按元素排序，因此相等的集合总是输出相同的字符串
+type Set struct {
+	Members map[HashKey]Object // 元素
+	Keys    []HashKey          // 元素的加入顺序
+}
+
+// NewSet 创建一个空集合
+func NewSet() *Set {
+	return &Set{Members: map[HashKey]Object{}}
+}
+
+// Type 方法返回对象的类型
+func (s *Set) Type() ObjectType {
+	return SET_OBJ
+}
+
+// Inspect 方法返回集合的字符串表示，例如 #{1, 2, "a"}。元素先按类型再按值排序，字符串带引号
+func (s *Set) Inspect() string {
+	members := make([]Object, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		members = append(members, s.Members[k])
+	}
+	sort.SliceStable(members, func(i, j int) bool { return lessElement(members[i], members[j]) })
+
+	elements := []string{}
+	for _, m := range members {
+		elements = append(elements, inspectElement(m))
+	}
+	return "#{" + strings.Join(elements, ", ") + "}"
+}
+
+// lessElement 给出集合元素的规范顺序：类型不同时按类型名排序，整数、字符和字符串按值排序，其他元素按字符串表示排序
+func lessElement(a, b Object) bool {
+	if a.Type() != b.Type() {
+		return a.Type() < b.Type()
+	}
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value < b.(*Integer).Value
+	case *Char:
+		return a.Value < b.(*Char).Value
+	case *String:
+		return a.Value < b.(*String).Value
+	}
+	return inspectElement(a) < inspectElement(b)
+}
+
+// inspectElement 返回容器中的元素的字符串表示，字符串带引号，以便与其他类型的值区分。
+// 所有容器的 Inspect 都通过它输出元素，因此嵌套容器中的字符串同样带引号
+func inspectElement(obj Object) string {
+	if s, ok := obj.(*String); ok {
+		return quoteString(s.Value)
+	}
+	return obj.Inspect()
+}
+
+// quoteString 返回字符串的字面量表示，引号、反斜杠、$ 和控制字符使用转义序列
+func quoteString(s string) string {
+	var out strings.Builder
+	out.WriteString("\"")
+	for _, c := range s {
+		switch c {
+		case '"', '\\', '$':
+			out.WriteString("\\" + string(c))
+		case '\n':
+			out.WriteString("\\n")
+		case '\t':
+			out.WriteString("\\t")
+		case '\r':
+			out.WriteString("\\r")
+		case 0:
+			out.WriteString("\\0")
+		default:
+			out.WriteRune(c)
+		}
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
+// Add 将元素加入集合，已存在的元素保持原来的位置
+func (s *Set) Add(elem Hashable) {
+	k := elem.HashKey()
+	if _, ok := s.Members[k]; !ok {
+		s.Keys = append(s.Keys, k)
+	}
+	s.Members[k] = elem
+}
+
+// Has 判断集合中是否存在该元素
+func (s *Set) Has(elem Hashable) bool {
+	_, ok := s.Members[elem.HashKey()]
+	return ok
+}
+
+// Tuple 结构体表示元组对象。元组不可变，所有元素都可以作为哈希键时元组也可以作为哈希键
+type Tuple struct {
+	Elements []Object // 元组元素
+}
+
+// Type 方法返回对象的类型
+func (t *Tuple) Type() ObjectType {
+	return TUPLE_OBJ
+}
+
+// Inspect 方法返回元组的字符串表示，例如 (1, "a") 或 (1,)，字符串元素带引号
+func (t *Tuple) Inspect() string {
+	elements := []string{}
+	for _, e := range t.Elements {
+		elements = append(elements, inspectElement(e))
+	}
+	if len(elements) == 1 {
+		return "(" + elements[0] + ",)"
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// HashKey 方法由各元素的键值组合出元组的键值，调用前需确认所有元素都可以作为哈希键
+func (t *Tuple) HashKey() HashKey {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, e := range t.Elements {
+		key := e.(Hashable).HashKey()
+		h.Write([]byte(key.Type))
+		binary.LittleEndian.PutUint64(buf[:], key.Value)
+		h.Write(buf[:])
+	}
+	return HashKey{Type: t.Type(), Value: h.Sum64()}
+}
+
+// HashKey 是可以作为哈希键的对象的键值，类型不同的对象键值一定不同
+type HashKey struct {
+	Type  ObjectType // 键对象的类型
+	Value uint64     // 键对象的哈希值
+}
+
+// Hashable 接口由可以作为哈希键的对象实现，相等的对象必须返回相同的键值
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+// HashPair 保存哈希中的一个键值对，保留原始的键对象用于输出
+type HashPair struct {
+	Key   Object // 键对象
+	Value Object // 值对象
+}
+
+// Hash 结构体表示哈希对象，遍历和输出时按键第一次插入的顺序进行
+type Hash struct {
+	Pairs map[HashKey]HashPair // 键值对
+	Keys  []HashKey            // 键的插入顺序
+}
+
+// NewHash 创建一个空的哈希对象
+func NewHash() *Hash {
+	return &Hash{Pairs: map[HashKey]HashPair{}}
+}
+
+// Type 方法返回对象的类型
+func (h *Hash) Type() ObjectType {
+	return HASH_OBJ
+}
+
+// Inspect 方法返回哈希的字符串表示，例如 {"a": 1, "b": "x"}，字符串键和值带引号
+func (h *Hash) Inspect() string {
+	pairs := []string{}
+	for _, k := range h.Keys {
+		pair := h.Pairs[k]
+		pairs = append(pairs, inspectElement(pair.Key)+": "+inspectElement(pair.Value))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Set 方法设置键对应的值，已存在的键保留原来的位置
+func (h *Hash) Set(key Hashable, val Object) {
+	k := key.HashKey()
+	if _, ok := h.Pairs[k]; !ok {
+		h.Keys = append(h.Keys, k) // 1. 新键追加到末尾
+	}
+	h.Pairs[k] = HashPair{Key: key, Value: val} // 2. 保存键值对
+}
+
+// Get 方法返回键对应的值
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+// Range 结构体表示整数区间。区间是惰性的，只保存起点、终点和步长，元素在遍历时才计算
+type Range struct {
+	Start     int64 // 起点
+	End       int64 // 终点
+	Step      int64 // 步长，不为 0，为负数时从大到小
+	Inclusive bool  // 是否包含终点
+}
+
+// Type 方法返回对象的类型
+func (r *Range) Type() ObjectType {
+	return RANGE_OBJ
+}
+
+// Inspect 方法返回区间的字符串表示，例如 1..=10 step 2
+func (r *Range) Inspect() string {
+	op := ".."
+	if r.Inclusive {
+		op = "..="
+	}
+	out := fmt.Sprintf("%d%s%d", r.Start, op, r.End)
+	if r.Step != 1 {
+		out += fmt.Sprintf(" step %d", r.Step)
+	}
+	return out
+}
+
+// Len 方法返回区间中元素的个数
+func (r *Range) Len() int64 {
+	last := r.End // 1. 计算最后一个可能的元素
+	if !r.Inclusive {
+		if r.Step > 0 {
+			last--
+		} else {
+			last++
+		}
+	}
+
+	if r.Step > 0 { // 2. 按步长的方向计算元素个数
+		if last < r.Start {
+			return 0
+		}
+		return (last-r.Start)/r.Step + 1
+	}
+	if last > r.Start {
+		return 0
+	}
+	return (r.Start-last)/-r.Step + 1
+}
+
+// At 方法返回区间中第 i 个元素，调用方需保证 0 <= i < Len()
+func (r *Range) At(i int64) int64 {
+	return r.Start + i*r.Step
+}
+
+// Contains 方法判断整数 n 是否为区间中的元素
+func (r *Range) Contains(n int64) bool {
+	offset := n - r.Start
+	if offset%r.Step != 0 { // 1. 必须落在步长上
+		return false
+	}
+	i := offset / r.Step
+	return i >= 0 && i < r.Len() // 2. 并且位于区间之内
+}
+
+// String 结构体表示字符串对象
+type String struct {
+	Value string // 字符串的值
+}
+
+// Type 方法返回对象的类型
+func (s *String) Type() ObjectType {
+	return STRING_OBJ
+}
+
+// Inspect 方法返回字符串的内容
+func (s *String) Inspect() string {
+	return s.Value
+}
+
+// HashKey 方法返回字符串作为哈希键时的键值
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+// Bytes 结构体表示字节串对象，与文本字符串不同，它按字节而不是按字符处理内容
+type Bytes struct {
+	Value []byte // 字节内容
+}
+
+// Type 方法返回对象的类型
+func (b *Bytes) Type() ObjectType {
+	return BYTES_OBJ
+}
+
+// Inspect 方法返回字节串的字面量表示，可打印的 ASCII 字符原样输出，其他字节使用转义序列，例如 b"ab\x00\xff"
+func (b *Bytes) Inspect() string {
+	var out strings.Builder
+	out.WriteString("b\"")
+	for _, c := range b.Value {
+		switch {
+		case c == '"' || c == '\\':
+			out.WriteString("\\" + string(c))
+		case c == '\n':
+			out.WriteString("\\n")
+		case c == '\t':
+			out.WriteString("\\t")
+		case c == '\r':
+			out.WriteString("\\r")
+		case c >= 0x20 && c < 0x7f:
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, "\\x%02x", c)
+		}
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
+// HashKey 方法返回字节串作为哈希键时的键值
+func (b *Bytes) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write(b.Value)
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// Char 结构体表示字符对象，即一个 Unicode 码点
+type Char struct {
+	Value rune // 字符
+}
+
+// Type 方法返回对象的类型
+func (c *Char) Type() ObjectType {
+	return CHAR_OBJ
+}
+
+// Inspect 方法返回字符的字面量表示，例如 'a'
+func (c *Char) Inspect() string {
+	switch c.Value {
+	case '\'', '\\':
+		return "'\\" + string(c.Value) + "'"
+	case '\n':
+		return "'\\n'"
+	case '\t':
+		return "'\\t'"
+	case '\r':
+		return "'\\r'"
+	case 0:
+		return "'\\0'"
+	}
+	return "'" + string(c.Value) + "'"
+}
+
+// HashKey 方法返回字符作为哈希键时的键值
+func (c *Char) HashKey() HashKey {
+	return HashKey{Type: c.Type(), Value: uint64(c.Value)}
+}
+
+// Regex 结构体表示编译后的正则表达式对象
+type Regex struct {
+	Pattern string         // 正则表达式模式
+	Flags   string         // 标志
+	Regexp  *regexp.Regexp // 编译结果，标志已经合并到模式中
+}
+
+// Type 方法返回对象的类型
+func (r *Regex) Type() ObjectType {
+	return REGEX_OBJ
+}
+
+// Inspect 方法返回正则表达式的字面量表示，例如 re"[a-z]+"i
+func (r *Regex) Inspect() string {
+	return "re\"" + r.Pattern + "\"" + r.Flags
+}
+
+// Error 结构体表示错误对象，它会中断求值并向外传播，直到被 catch 捕获
 type Error struct {
+	Kind    string // 错误种类
 	Message string // 错误信息
+	Value   Object // throw 抛出的原始值，内置错误为 nil
 }
 
 // Type 方法返回对象的类型
@@ -50,10 +532,38 @@ func (e *Error) Inspect() string {
 	return "ERROR: " + e.Message
 }
 
-// Environment 结构体表示变量环境，支持嵌套
+// ErrorValue 结构体表示被 catch 捕获后的错误，它是普通的值，不会中断求值
+type ErrorValue struct {
+	Kind    string // 错误种类
+	Message string // 错误信息
+	Value   Object // throw 抛出的原始值，内置错误为 nil
+}
+
+// Type 方法返回对象的类型
+func (ev *ErrorValue) Type() ObjectType {
+	return ERROR_VALUE_OBJ
+}
+
+// Inspect 方法返回错误值的字符串表示，例如 "NameError: identifier not found: x"
+func (ev *ErrorValue) Inspect() string {
+	return ev.Kind + ": " + ev.Message
+}
+
+// Environment 结构体表示变量环境，支持嵌套；spawn 创建的任务会共享环境，因此读写需要加锁
 type Environment struct {
-	store map[string]Object // 存储变量名到对象的映射
-	outer *Environment      // 外层环境，支持嵌套作用域
+	mu     sync.RWMutex      // 保护 store 的并发读写
+	store  map[string]Object // 存储变量名到对象的映射
+	outer  *Environment      // 外层环境，支持嵌套作用域
+	frame  *Frame            // 函数调用帧，只有函数调用创建的环境才会设置
+	module *Module           // 所属模块，只有模块的顶层环境才会设置
+}
+
+// Frame 结构体表示一次函数调用的调用帧
+type Frame struct {
+	Generator  *Generator      // 正在执行的生成器，普通函数调用为 nil
+	Defers     []func() Object // defer 注册的调用，函数结束时按后进先出的顺序执行
+	Panic      *Error          // 执行 defer 时尚未被 recover 的错误
+	DeferredBy *Frame          // 本次调用由 defer 发起时，注册它的函数的调用帧，recover 通过它找到错误
 }
 
 // NewEnvironment 创建一个新的环境实例
@@ -62,9 +572,46 @@ func NewEnvironment(env *Environment) *Environment {
 	return &Environment{store: s, outer: env} // 2. 返回包含新映射和无外层环境的Environment实例
 }
 
+// NewFrameEnvironment 创建一个函数调用使用的环境实例，并关联调用帧
+func NewFrameEnvironment(env *Environment, frame *Frame) *Environment {
+	e := NewEnvironment(env) // 1. 创建嵌套在闭包环境中的新环境
+	e.frame = frame          // 2. 关联本次调用的调用帧
+	return e                 // 3. 返回新环境
+}
+
+// NewModuleEnvironment 创建模块的顶层环境实例
+func NewModuleEnvironment(module *Module) *Environment {
+	e := NewEnvironment(nil) // 1. 模块的顶层环境没有外层环境
+	e.module = module        // 2. 关联所属模块
+	return e                 // 3. 返回新环境
+}
+
+// Module 方法沿外层环境查找所属的模块，REPL 中的代码不属于任何模块，返回 nil
+func (e *Environment) Module() *Module {
+	for env := e; env != nil; env = env.outer { // 1. 从当前环境逐层向外查找
+		if env.module != nil {
+			return env.module // 2. 找到模块的顶层环境
+		}
+	}
+	return nil // 3. 不在任何模块中
+}
+
+// Frame 方法沿外层环境查找最近的调用帧，不在任何函数中时返回 nil
+func (e *Environment) Frame() *Frame {
+	for env := e; env != nil; env = env.outer { // 1. 从当前环境逐层向外查找
+		if env.frame != nil {
+			return env.frame // 2. 找到最近的调用帧
+		}
+	}
+	return nil // 3. 顶层代码没有调用帧
+}
+
 // Get 方法根据变量名获取对应的对象
 func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]   // 1. 尝试在当前环境中查找变量
+	e.mu.RLock()
+	obj, ok := e.store[name] // 1. 尝试在当前环境中查找变量
+	e.mu.RUnlock()
+
 	if !ok && e.outer != nil { // 2. 如果未找到且存在外层环境
 		obj, ok = e.outer.Get(name) // 3. 递归在外层环境中查找变量
 	}
@@ -73,8 +620,11 @@ func (e *Environment) Get(name string) (Object, bool) {
 
 // Set 方法在环境中设置一个变量
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
 	e.store[name] = val // 1. 在当前环境的存储中设置变量名和对应的对象
-	return val          // 2. 返回设置的对象
+	e.mu.Unlock()
+
+	return val // 2. 返回设置的对象
 }
 
 // ReturnValue 结构体表示返回值对象
@@ -91,3 +641,273 @@ func (rv *ReturnValue) Type() ObjectType {
 func (rv *ReturnValue) Inspect() string {
 	return rv.Value.Inspect()
 }
+
+// Enum 结构体表示由 enum 声明定义的枚举类型；spawn 创建的任务可能在 impl 语句执行时调用方法，因此方法表的读写需要加锁
+type Enum struct {
+	Name     string               // 枚举名称
+	Variants []*Variant           // 枚举包含的变体
+	mu       sync.RWMutex         // 保护 methods 的并发读写
+	methods  map[string]*Function // impl 语句定义的方法，包括重载操作符的特殊方法
+}
+
+// Method 方法根据方法名查找 impl 语句定义的方法
+func (e *Enum) Method(name string) (*Function, bool) {
+	e.mu.RLock()
+	method, ok := e.methods[name]
+	e.mu.RUnlock()
+	return method, ok
+}
+
+// SetMethod 方法定义或替换一个方法
+func (e *Enum) SetMethod(name string, method *Function) {
+	e.mu.Lock()
+	if e.methods == nil { // 1. 第一次定义方法时创建方法表
+		e.methods = map[string]*Function{}
+	}
+	e.methods[name] = method // 2. 同名方法被后面的 impl 语句替换
+	e.mu.Unlock()
+}
+
+// Type 方法返回对象的类型
+func (e *Enum) Type() ObjectType {
+	return ENUM_OBJ
+}
+
+// Inspect 方法返回枚举声明的字符串表示，例如 "enum Shape { Circle(r), Empty }"
+func (e *Enum) Inspect() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range e.Variants {
+		variants = append(variants, v.Inspect()) // 1. 收集每个变体的字符串表示
+	}
+
+	out.WriteString("enum " + e.Name + " { ")     // 2. 写入枚举名称
+	out.WriteString(strings.Join(variants, ", ")) // 3. 写入以逗号分隔的变体
+	out.WriteString(" }")                         // 4. 写入右大括号
+
+	return out.String()
+}
+
+// Variant 结构体表示枚举中的一个变体，带字段的变体同时充当构造函数
+type Variant struct {
+	Enum   *Enum    // 变体所属的枚举
+	Name   string   // 变体名称，即标签
+	Fields []string // 变体携带的字段名
+}
+
+// Type 方法返回对象的类型
+func (v *Variant) Type() ObjectType {
+	return VARIANT_OBJ
+}
+
+// Inspect 方法返回变体的字符串表示，例如 "Rect(w, h)"
+func (v *Variant) Inspect() string {
+	if len(v.Fields) == 0 {
+		return v.Name
+	}
+	return v.Name + "(" + strings.Join(v.Fields, ", ") + ")"
+}
+
+// EnumValue 结构体表示枚举值，由变体标签和负载组成
+type EnumValue struct {
+	Variant *Variant // 枚举值的变体，即标签
+	Values  []Object // 负载，与变体的字段一一对应
+}
+
+// Type 方法返回对象的类型
+func (ev *EnumValue) Type() ObjectType {
+	return ENUM_VALUE_OBJ
+}
+
+// Inspect 方法返回枚举值的字符串表示，例如 Circle(5)、Named("a") 或 Empty，字符串负载带引号
+func (ev *EnumValue) Inspect() string {
+	if len(ev.Values) == 0 {
+		return ev.Variant.Name // 1. 无负载的枚举值只输出变体名称
+	}
+
+	values := []string{}
+	for _, v := range ev.Values {
+		values = append(values, inspectElement(v)) // 2. 收集负载的字符串表示
+	}
+
+	return ev.Variant.Name + "(" + strings.Join(values, ", ") + ")" // 3. 输出变体名称和负载
+}
+
+// Function 结构体表示用户定义的函数对象，它捕获了定义时的环境
+type Function struct {
+	Name        string              // 函数名，匿名函数为空
+	IsGenerator bool                // 是否为生成器函数
+	Parameters  []*ast.Identifier   // 形参列表
+	Defaults    []ast.Expression    // 形参的默认值，没有默认值的形参对应 nil
+	Rest        *ast.Identifier     // 可变参数，没有时为 nil
+	Body        *ast.BlockStatement // 函数体
+	Env         *Environment        // 定义函数时的环境，即闭包环境
+}
+
+// Type 方法返回对象的类型
+func (f *Function) Type() ObjectType {
+	return FUNCTION_OBJ
+}
+
+// Inspect 方法返回函数的字符串表示，例如 "fn add(x, y) { (x + y) }"
+func (f *Function) Inspect() string {
+	lit := &ast.FunctionLiteral{
+		Token:       token.Token{Type: token.FUNCTION, Literal: "fn"},
+		Name:        f.Name,
+		IsGenerator: f.IsGenerator,
+		Parameters:  f.Parameters,
+		Defaults:    f.Defaults,
+		Rest:        f.Rest,
+		Body:        f.Body,
+	}
+	return lit.String() // 1. 与函数字面量的字符串表示保持一致
+}
+
+// BuiltinFunction 定义内置函数的签名
+type BuiltinFunction func(args ...Object) Object
+
+// Builtin 结构体表示内置函数对象
+type Builtin struct {
+	Name string          // 内置函数名
+	Fn   BuiltinFunction // 内置函数的实现
+}
+
+// Type 方法返回对象的类型
+func (b *Builtin) Type() ObjectType {
+	return BUILTIN_OBJ
+}
+
+// Inspect 方法返回内置函数的字符串表示
+func (b *Builtin) Inspect() string {
+	return "builtin " + b.Name
+}
+
+// Generator 结构体表示调用生成器函数得到的惰性迭代器，函数体在独立的 goroutine 中执行，
+// 每次 yield 都会把值交给调用方并暂停，直到下一次被恢复
+type Generator struct {
+	Name   string        // 生成器函数名
+	Resume chan struct{} // 调用方通过它恢复函数体的执行
+	Yield  chan Object   // 函数体通过它交出产出的值
+	Stop   chan struct{} // 调用方放弃生成器时关闭，暂停中的函数体随之结束
+	Done   bool          // 函数体是否已经执行完毕或被关闭
+}
+
+// Type 方法返回对象的类型
+func (g *Generator) Type() ObjectType {
+	return GENERATOR_OBJ
+}
+
+// Inspect 方法返回生成器的字符串表示
+func (g *Generator) Inspect() string {
+	return "<generator " + g.Name + ">"
+}
+
+// Task 结构体表示 spawn 启动的并发任务，任务中的错误会保存下来，在 wait 时重新抛出
+type Task struct {
+	Done   chan struct{} // 任务结束时关闭
+	Result Object        // 任务的结果，可能是错误对象，只能在 Done 关闭后读取
+}
+
+// Type 方法返回对象的类型
+func (t *Task) Type() ObjectType {
+	return TASK_OBJ
+}
+
+// Inspect 方法返回任务的字符串表示
+func (t *Task) Inspect() string {
+	select {
+	case <-t.Done:
+		return "<task done>" // 1. 任务已经结束
+	default:
+		return "<task running>" // 2. 任务仍在运行
+	}
+}
+
+// Channel 结构体表示用于任务间通信的通道
+type Channel struct {
+	Ch     chan Object // 底层的 Go 通道
+	mu     sync.Mutex  // 保护 closed 标记
+	closed bool        // 通道是否已经关闭
+}
+
+// Type 方法返回对象的类型
+func (c *Channel) Type() ObjectType {
+	return CHANNEL_OBJ
+}
+
+// Inspect 方法返回通道的字符串表示，例如 "chan(2)"
+func (c *Channel) Inspect() string {
+	return fmt.Sprintf("chan(%d)", cap(c.Ch))
+}
+
+// Close 方法关闭通道，重复关闭时返回 false
+func (c *Channel) Close() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed { // 1. 已经关闭的通道不能再次关闭
+		return false
+	}
+	c.closed = true // 2. 标记并关闭底层通道
+	close(c.Ch)
+	return true
+}
+
+// Module 结构体表示一个已导入的模块，每个模块文件只会被求值一次
+type Module struct {
+	Name     string          // 模块名，即文件名去掉扩展名
+	Path     string          // 模块文件的绝对路径
+	Env      *Environment    // 模块的顶层环境
+	Exports  map[string]bool // 导出的名字
+	Importer *Module         // 第一次导入该模块的模块，用于报告循环导入
+	Loaded   chan struct{}   // 模块求值结束时关闭
+	Err      *Error          // 模块求值过程中产生的错误
+}
+
+// Type 方法返回对象的类型
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+
+// Inspect 方法返回模块的字符串表示
+func (m *Module) Inspect() string {
+	return "<module " + m.Name + ">"
+}
+
+// Quote 结构体表示 quote 得到的未求值的 AST，使 AST 可以作为值传递
+type Quote struct {
+	Node ast.Node // 被引用的 AST 节点
+}
+
+// Type 方法返回对象的类型
+func (q *Quote) Type() ObjectType {
+	return QUOTE_OBJ
+}
+
+// Inspect 方法返回被引用 AST 的字符串表示，例如 "QUOTE((1 + 2))"
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+// Macro 结构体表示宏对象，宏在求值之前展开，实参以 Quote 的形式传入
+type Macro struct {
+	Parameters []*ast.Identifier   // 形参列表
+	Body       *ast.BlockStatement // 宏体
+	Env        *Environment        // 定义宏时的环境
+}
+
+// Type 方法返回对象的类型
+func (m *Macro) Type() ObjectType {
+	return MACRO_OBJ
+}
+
+// Inspect 方法返回宏的字符串表示
+func (m *Macro) Inspect() string {
+	lit := &ast.MacroLiteral{
+		Token:      token.Token{Type: token.MACRO, Literal: "macro"},
+		Parameters: m.Parameters,
+		Body:       m.Body,
+	}
+	return lit.String() // 1. 与宏字面量的字符串表示保持一致
+}
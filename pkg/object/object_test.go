@@ -0,0 +1,61 @@
+package object
+
+import "testing"
+
+func newTestSet(elements ...Hashable) *Set {
+	s := NewSet()
+	for _, e := range elements {
+		s.Add(e)
+	}
+	return s
+}
+
+func TestSetInspectIsCanonical(t *testing.T) {
+	a := newTestSet(&Integer{Value: 3}, &Integer{Value: 1}, &Integer{Value: 2})
+	b := newTestSet(&Integer{Value: 1}, &Integer{Value: 2}, &Integer{Value: 3})
+	if a.Inspect() != b.Inspect() || a.Inspect() != "#{1, 2, 3}" {
+		t.Errorf("got %s and %s, want #{1, 2, 3}", a.Inspect(), b.Inspect())
+	}
+
+	mixed := newTestSet(&String{Value: "1"}, &Integer{Value: 10}, &Integer{Value: 1})
+	if got := mixed.Inspect(); got != `#{1, 10, "1"}` {
+		t.Errorf(`got %s, want #{1, 10, "1"}`, got)
+	}
+}
+
+func TestHashInspectQuotesStrings(t *testing.T) {
+	h := NewHash()
+	h.Set(&String{Value: "a"}, &String{Value: "x\"y"})
+	h.Set(&Integer{Value: 1}, &Integer{Value: 2})
+	if got := h.Inspect(); got != `{"a": "x\"y", 1: 2}` {
+		t.Errorf(`got %s, want {"a": "x\"y", 1: 2}`, got)
+	}
+}
+
+func TestContainersQuoteStrings(t *testing.T) {
+	str := func(s string) *String { return &String{Value: s} }
+	one := &Integer{Value: 1}
+	inner := NewHash()
+	inner.Set(str("k"), &Array{Elements: []Object{str("v")}})
+	variant := &Variant{Name: "Named", Fields: []string{"name"}}
+
+	tests := []struct {
+		obj  Object
+		want string
+	}{
+		{str("top"), `top`},
+		{&Array{Elements: []Object{one, str("1")}}, `[1, "1"]`},
+		{&Tuple{Elements: []Object{str("a")}}, `("a",)`},
+		{&Tuple{Elements: []Object{one, str("a\nb")}}, `(1, "a\nb")`},
+		{&EnumValue{Variant: variant, Values: []Object{str("x")}}, `Named("x")`},
+		{&Array{Elements: []Object{&Tuple{Elements: []Object{str("a"), &Array{Elements: []Object{str("b")}}}}}}, `[("a", ["b"])]`},
+		{&Array{Elements: []Object{inner}}, `[{"k": ["v"]}]`},
+		{&EnumValue{Variant: variant, Values: []Object{&Array{Elements: []Object{str("x")}}}}, `Named(["x"])`},
+		{newTestSet(&Tuple{Elements: []Object{str("a"), one}}), `#{("a", 1)}`},
+	}
+	for _, tt := range tests {
+		if got := tt.obj.Inspect(); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
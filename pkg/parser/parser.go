@@ -2,7 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"punyGo/pkg/ast"
 	"punyGo/pkg/lexer"
@@ -13,30 +16,53 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	COALESCE    // ??
 	EQUALS      // ==
-	LESSGREATER // > 或 <
+	LESSGREATER // > 或 < 或 in
+	RANGE       // .. 或 ..=
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X 或 !X
+	POSTFIX     // X?
 	CALL        // myFunction(X)
 )
 
 // 定义每个Token类型对应的优先级
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
+	token.COALESCE:          COALESCE,
+	token.EQ:                EQUALS,
+	token.NOT_EQ:            EQUALS,
+	token.REGEX_MATCH:       EQUALS,
+	token.LT:                LESSGREATER,
+	token.GT:                LESSGREATER,
+	token.IN:                LESSGREATER,
+	token.IS:                LESSGREATER,
+	token.DOTDOT:            RANGE,
+	token.DOTDOT_EQ:         RANGE,
+	token.PIPE:              BITOR,
+	token.CARET:             BITXOR,
+	token.AMPERSAND:         BITAND,
+	token.PLUS:              SUM,
+	token.MINUS:             SUM,
+	token.SLASH:             PRODUCT,
+	token.ASTERISK:          PRODUCT,
+	token.PERCENT:           PRODUCT,
+	token.QUESTION:          POSTFIX,
+	token.LPAREN:            CALL,
+	token.DOT:               CALL,
+	token.OPTIONAL_DOT:      CALL,
+	token.LBRACKET:          CALL,
+	token.OPTIONAL_LBRACKET: CALL,
 }
 
-// 定义前缀解析函数类型
+// 定义前缀、中缀和后缀解析函数类型
 type (
-	prefixParseFn func() ast.Expression
-	infixParseFn  func(ast.Expression) ast.Expression
+	prefixParseFn  func() ast.Expression
+	infixParseFn   func(ast.Expression) ast.Expression
+	postfixParseFn func(ast.Expression) ast.Expression
 )
 
 // Parser 结构体定义了解析器的状态和方法
@@ -47,8 +73,9 @@ type Parser struct {
 	curToken  token.Token // 当前处理的Token
 	peekToken token.Token // 下一个Token
 
-	prefixParseFns map[token.TokenType]prefixParseFn // 前缀解析函数映射
-	infixParseFns  map[token.TokenType]infixParseFn  // 中缀解析函数映射
+	prefixParseFns  map[token.TokenType]prefixParseFn  // 前缀解析函数映射
+	infixParseFns   map[token.TokenType]infixParseFn   // 中缀解析函数映射
+	postfixParseFns map[token.TokenType]postfixParseFn // 后缀解析函数映射
 }
 
 // New 创建并返回一个新的 Parser 实例
@@ -60,22 +87,57 @@ func New(lex *lexer.Lexer) *Parser {
 
 	// 初始化前缀解析函数映射
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
-	p.registerPrefix(token.IDENT, p.parseIdentifier)         // 1. 注册标识符解析函数
-	p.registerPrefix(token.INT, p.parseIntegerLiteral)       // 2. 注册整数字面量解析函数
-	p.registerPrefix(token.BANG, p.parsePrefixExpression)    // 3. 注册逻辑非解析函数
-	p.registerPrefix(token.MINUS, p.parsePrefixExpression)   // 4. 注册负号解析函数
-	p.registerPrefix(token.LPAREN, p.parseGroupedExpression) // 5. 注册分组表达式解析函数
+	p.registerPrefix(token.IDENT, p.parseIdentifier)          // 1. 注册标识符解析函数
+	p.registerPrefix(token.INT, p.parseIntegerLiteral)        // 2. 注册整数字面量解析函数
+	p.registerPrefix(token.BANG, p.parsePrefixExpression)     // 3. 注册逻辑非解析函数
+	p.registerPrefix(token.MINUS, p.parsePrefixExpression)    // 4. 注册负号解析函数
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)  // 5. 注册分组表达式解析函数
+	p.registerPrefix(token.TRUE, p.parseBoolean)              // 6. 注册布尔字面量 true 解析函数
+	p.registerPrefix(token.FALSE, p.parseBoolean)             // 7. 注册布尔字面量 false 解析函数
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)  // 8. 注册函数字面量解析函数
+	p.registerPrefix(token.SPAWN, p.parseSpawnExpression)     // 9. 注册spawn表达式解析函数
+	p.registerPrefix(token.STRING, p.parseStringLiteral)      // 10. 注册字符串字面量解析函数
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)        // 11. 注册宏字面量解析函数
+	p.registerPrefix(token.NIL, p.parseNilLiteral)            // 12. 注册空值字面量解析函数
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)     // 13. 注册数组字面量解析函数
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)        // 14. 注册哈希字面量解析函数
+	p.registerPrefix(token.ELLIPSIS, p.parseSpreadExpression) // 15. 注册展开表达式解析函数
+	p.registerPrefix(token.IF, p.parseIfExpression)           // 16. 注册条件表达式解析函数
+	p.registerPrefix(token.SET_LBRACE, p.parseSetLiteral)     // 17. 注册集合字面量解析函数
+	p.registerPrefix(token.BYTES, p.parseBytesLiteral)        // 18. 注册字节串字面量解析函数
+	p.registerPrefix(token.CHAR, p.parseCharLiteral)          // 19. 注册字符字面量解析函数
+	p.registerPrefix(token.REGEX, p.parseRegexLiteral)        // 20. 注册正则表达式字面量解析函数
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)     // 21. 注册 match 表达式解析函数
 
 	// 初始化中缀解析函数映射
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
-	p.registerInfix(token.PLUS, p.parseInfixExpression)     // 1. 注册加法解析函数
-	p.registerInfix(token.MINUS, p.parseInfixExpression)    // 2. 注册减法解析函数
-	p.registerInfix(token.SLASH, p.parseInfixExpression)    // 3. 注册除法解析函数
-	p.registerInfix(token.ASTERISK, p.parseInfixExpression) // 4. 注册乘法解析函数
-	p.registerInfix(token.EQ, p.parseInfixExpression)       // 5. 注册等于比较解析函数
-	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)   // 6. 注册不等于比较解析函数
-	p.registerInfix(token.LT, p.parseInfixExpression)       // 7. 注册小于比较解析函数
-	p.registerInfix(token.GT, p.parseInfixExpression)       // 8. 注册大于比较解析函数
+	p.registerInfix(token.PLUS, p.parseInfixExpression)              // 1. 注册加法解析函数
+	p.registerInfix(token.MINUS, p.parseInfixExpression)             // 2. 注册减法解析函数
+	p.registerInfix(token.SLASH, p.parseInfixExpression)             // 3. 注册除法解析函数
+	p.registerInfix(token.ASTERISK, p.parseInfixExpression)          // 4. 注册乘法解析函数
+	p.registerInfix(token.EQ, p.parseInfixExpression)                // 5. 注册等于比较解析函数
+	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)            // 6. 注册不等于比较解析函数
+	p.registerInfix(token.LT, p.parseInfixExpression)                // 7. 注册小于比较解析函数
+	p.registerInfix(token.GT, p.parseInfixExpression)                // 8. 注册大于比较解析函数
+	p.registerInfix(token.LPAREN, p.parseCallExpression)             // 9. 注册调用表达式解析函数
+	p.registerInfix(token.DOT, p.parseMemberExpression)              // 10. 注册成员访问解析函数
+	p.registerInfix(token.OPTIONAL_DOT, p.parseMemberExpression)     // 11. 注册可选成员访问解析函数
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)          // 12. 注册下标表达式解析函数
+	p.registerInfix(token.OPTIONAL_LBRACKET, p.parseIndexExpression) // 13. 注册可选下标表达式解析函数
+	p.registerInfix(token.COALESCE, p.parseInfixExpression)          // 14. 注册空值合并解析函数
+	p.registerInfix(token.IN, p.parseInfixExpression)                // 15. 注册成员测试解析函数
+	p.registerInfix(token.DOTDOT, p.parseRangeExpression)            // 16. 注册区间解析函数
+	p.registerInfix(token.DOTDOT_EQ, p.parseRangeExpression)         // 17. 注册闭区间解析函数
+	p.registerInfix(token.PIPE, p.parseInfixExpression)              // 18. 注册并集解析函数
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)         // 19. 注册交集解析函数
+	p.registerInfix(token.CARET, p.parseInfixExpression)             // 20. 注册对称差解析函数
+	p.registerInfix(token.REGEX_MATCH, p.parseInfixExpression)       // 21. 注册正则匹配解析函数
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)           // 22. 注册取余解析函数
+	p.registerInfix(token.IS, p.parseInfixExpression)                // 23. 注册变体测试解析函数
+
+	// 初始化后缀解析函数映射
+	p.postfixParseFns = make(map[token.TokenType]postfixParseFn)
+	p.registerPostfix(token.QUESTION, p.parsePostfixExpression) // 1. 注册 '?' 传播操作符解析函数
 
 	// 读取两个Token，初始化curToken和peekToken
 	p.nextToken() // 1. 读取第一个Token
@@ -132,16 +194,23 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 	}
 }
 
+// errorAt 记录一条错误，Token带有位置信息时在错误消息前加上 "行:列: "
+func (p *Parser) errorAt(tok token.Token, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...) // 1. 构建错误消息
+	if tok.Line > 0 {                   // 2. 加上Token的起始位置
+		msg = fmt.Sprintf("%d:%d: %s", tok.Line, tok.Column, msg)
+	}
+	p.errors = append(p.errors, msg) // 3. 将错误消息添加到错误列表
+}
+
 // peekError 记录期待的Token类型与实际不匹配的错误
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type) // 1. 构建错误消息
-	p.errors = append(p.errors, msg)                                                        // 2. 将错误消息添加到错误列表
+	p.errorAt(p.peekToken, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
 }
 
 // noPrefixParseFnError 记录缺少前缀解析函数的错误
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t) // 1. 构建错误消息
-	p.errors = append(p.errors, msg)                               // 2. 将错误消息添加到错误列表
+	p.errorAt(p.curToken, "no prefix parse function for %s found", t)
 }
 
 // registerPrefix 注册前缀解析函数
@@ -154,12 +223,41 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn // 1. 将中缀解析函数与Token类型关联
 }
 
+// registerPostfix 注册后缀解析函数
+func (p *Parser) registerPostfix(tokenType token.TokenType, fn postfixParseFn) {
+	p.postfixParseFns[tokenType] = fn // 1. 将后缀解析函数与Token类型关联
+}
+
 // parseStatement 根据当前Token解析不同类型的语句
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET: // 1. 如果是let语句
 		return p.parseLetStatement() // 1.1. 解析let语句
-	default: // 2. 默认解析为表达式语句
+	case token.ENUM: // 2. 如果是enum声明
+		return p.parseEnumStatement() // 2.1. 解析enum声明
+	case token.THROW: // 3. 如果是throw语句
+		return p.parseThrowStatement() // 3.1. 解析throw语句
+	case token.TRY: // 4. 如果是try语句
+		return p.parseTryStatement() // 4.1. 解析try语句
+	case token.RETURN: // 5. 如果是return语句
+		return p.parseReturnStatement() // 5.1. 解析return语句
+	case token.YIELD: // 6. 如果是yield语句
+		return p.parseYieldStatement() // 6.1. 解析yield语句
+	case token.FUNCTION: // 7. 如果以fn开头，可能是具名函数声明
+		return p.parseFunctionStatement() // 7.1. 解析函数声明
+	case token.SELECT: // 8. 如果是select语句
+		return p.parseSelectStatement() // 8.1. 解析select语句
+	case token.IMPORT: // 9. 如果是import语句
+		return p.parseImportStatement() // 9.1. 解析import语句
+	case token.EXPORT: // 10. 如果是export声明
+		return p.parseExportStatement() // 10.1. 解析export声明
+	case token.FOR: // 11. 如果是for循环
+		return p.parseForStatement() // 11.1. 解析for循环
+	case token.DEFER: // 12. 如果是defer语句
+		return p.parseDeferStatement() // 12.1. 解析defer语句
+	case token.IMPL: // 13. 如果是impl语句
+		return p.parseImplStatement() // 13.1. 解析impl语句
+	default: // 14. 默认解析为表达式语句
 		return p.parseExpressionStatement()
 	}
 }
@@ -168,13 +266,30 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken} // 1. 创建一个新的LetStatement节点，记录当前Token
 
-	if !p.expectPeek(token.IDENT) { // 2.1. 期待下一个Token是标识符
-		return nil // 2.2. 如果不是，返回nil
+	if p.peekTokenIs(token.LPAREN) { // 2. 以左括号开头的是解构赋值
+		p.nextToken()
+		stmt.Names = p.parseIdentifierList(token.RPAREN) // 2.0.1. 解析变量名列表
+		if len(stmt.Names) == 0 {
+			p.errorAt(stmt.Token, "destructuring let needs at least one name")
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) { // 2.1. 期待下一个Token是标识符
+			return nil // 2.2. 如果不是，返回nil
+		}
+
+		stmt.Name = &ast.Identifier{ // 3. 设置变量名
+			Token: p.curToken,         // 3.1. 当前Token
+			Value: p.curToken.Literal, // 3.2. 变量名的字面量
+		}
 	}
 
-	stmt.Name = &ast.Identifier{ // 3. 设置变量名
-		Token: p.curToken,         // 3.1. 当前Token
-		Value: p.curToken.Literal, // 3.2. 变量名的字面量
+	if p.peekTokenIs(token.COLON) { // 3.3. 解析可选的类型标注
+		p.nextToken()
+		p.nextToken()
+		if stmt.Type = p.parseTypeExpression(); stmt.Type == nil {
+			return nil
+		}
 	}
 
 	if !p.expectPeek(token.ASSIGN) { // 4.1. 期待下一个Token是赋值操作符
@@ -185,11 +300,488 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	stmt.Value = p.parseExpression(LOWEST) // 6. 解析赋值表达式，优先级最低
 
+	if fl, ok := stmt.Value.(*ast.FunctionLiteral); ok && fl.Name == "" && stmt.Name != nil { // 7. 匿名函数以变量名作为函数名
+		fl.Name = stmt.Name.Value
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 8. 如果下一个Token是分号
+		p.nextToken() // 8.1. 前进到分号
+	}
+
+	return stmt // 9. 返回解析后的LetStatement节点
+}
+
+// parseEnumStatement 解析enum声明，例如 enum Shape { Circle(r), Rect(w, h), Empty }
+func (p *Parser) parseEnumStatement() ast.Statement {
+	stmt := &ast.EnumStatement{Token: p.curToken} // 1. 创建一个新的EnumStatement节点，记录当前Token
+
+	if !p.expectPeek(token.IDENT) { // 2. 期待下一个Token是枚举名称
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal} // 3. 设置枚举名称
+
+	if p.peekTokenIs(token.LT) { // 3.1. 解析可选的类型参数
+		p.nextToken()
+		if stmt.TypeParams = p.parseTypeParams(); stmt.TypeParams == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) { // 4. 期待下一个Token是左大括号
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) { // 5. 循环解析变体，直到遇到右大括号
+		if !p.expectPeek(token.IDENT) { // 5.1. 每个变体都以名称开头
+			return nil
+		}
+		variant := &ast.EnumVariant{
+			Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		}
+
+		if p.peekTokenIs(token.LPAREN) { // 5.2. 如果变体带有字段列表
+			p.nextToken()
+			if !p.parseVariantFields(variant) { // 5.2.1. 解析字段名和可选的类型标注
+				return nil
+			}
+		}
+		stmt.Variants = append(stmt.Variants, variant) // 5.3. 记录变体
+
+		if !p.peekTokenIs(token.COMMA) { // 5.4. 变体之间以逗号分隔，允许末尾逗号
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBRACE) { // 6. 期待下一个Token是右大括号
+		return nil
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) { // 7. 如果下一个Token是分号
 		p.nextToken() // 7.1. 前进到分号
 	}
 
-	return stmt // 8. 返回解析后的LetStatement节点
+	return stmt // 8. 返回解析后的EnumStatement节点
+}
+
+// parseVariantFields 解析枚举变体的字段列表，当前Token为左括号，结束时当前Token为右括号。
+// 每个字段可以用 ": 类型" 标注类型，例如 Some(value: T)
+func (p *Parser) parseVariantFields(variant *ast.EnumVariant) bool {
+	variant.Fields = []*ast.Identifier{}
+	variant.FieldTypes = []ast.TypeExpression{}
+
+	for !p.peekTokenIs(token.RPAREN) {
+		if !p.expectPeek(token.IDENT) { // 1. 每个字段都以名称开头
+			return false
+		}
+		field := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		var typ ast.TypeExpression
+		if p.peekTokenIs(token.COLON) { // 2. 解析可选的类型标注
+			p.nextToken()
+			p.nextToken()
+			if typ = p.parseTypeExpression(); typ == nil {
+				return false
+			}
+		}
+		variant.Fields = append(variant.Fields, field)
+		variant.FieldTypes = append(variant.FieldTypes, typ)
+
+		if !p.peekTokenIs(token.COMMA) { // 3. 没有逗号时字段列表结束
+			break
+		}
+		p.nextToken()
+	}
+
+	return p.expectPeek(token.RPAREN) // 4. 期待列表以右括号收尾
+}
+
+// parseTypeParams 解析尖括号中的类型参数列表，例如 <A, B>，当前Token为 '<'，结束时当前Token为 '>'
+func (p *Parser) parseTypeParams() []*ast.Identifier {
+	params := []*ast.Identifier{}
+	seen := map[string]bool{}
+
+	for {
+		if !p.expectPeek(token.IDENT) { // 1. 每个类型参数都是标识符
+			return nil
+		}
+		if seen[p.curToken.Literal] {
+			p.errorAt(p.curToken, "duplicate type parameter %s", p.curToken.Literal)
+			return nil
+		}
+		seen[p.curToken.Literal] = true
+		params = append(params, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.COMMA) { // 2. 没有逗号时列表结束
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.GT) { // 3. 期待列表以 '>' 收尾
+		return nil
+	}
+	return params
+}
+
+// parseReturnStatement 解析return语句
+func (p *Parser) parseReturnStatement() ast.Statement {
+	stmt := &ast.ReturnStatement{Token: p.curToken} // 1. 创建一个新的ReturnStatement节点，记录当前Token
+
+	p.nextToken()                                // 2. 前进到返回值表达式
+	stmt.ReturnValue = p.parseExpression(LOWEST) // 3. 解析返回值表达式
+	if stmt.ReturnValue == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.COMMA) { // 3.1. "return a, b" 返回一个元组
+		tuple := &ast.TupleLiteral{Token: stmt.Token, Elements: []ast.Expression{stmt.ReturnValue}}
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			p.nextToken()
+			elem := p.parseExpression(LOWEST)
+			if elem == nil {
+				return nil
+			}
+			tuple.Elements = append(tuple.Elements, elem)
+		}
+		stmt.ReturnValue = tuple
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 4. 如果下一个Token是分号
+		p.nextToken() // 4.1. 前进到分号
+	}
+
+	return stmt // 5. 返回解析后的ReturnStatement节点
+}
+
+// parseYieldStatement 解析yield语句
+func (p *Parser) parseYieldStatement() ast.Statement {
+	stmt := &ast.YieldStatement{Token: p.curToken} // 1. 创建一个新的YieldStatement节点，记录当前Token
+
+	p.nextToken()                          // 2. 前进到产出值表达式
+	stmt.Value = p.parseExpression(LOWEST) // 3. 解析产出值表达式
+	if stmt.Value == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 4. 如果下一个Token是分号
+		p.nextToken() // 4.1. 前进到分号
+	}
+
+	return stmt // 5. 返回解析后的YieldStatement节点
+}
+
+// parseImplStatement 解析impl语句，例如 impl Vec { fn __add__(self, other) { ... } }
+func (p *Parser) parseImplStatement() ast.Statement {
+	stmt := &ast.ImplStatement{Token: p.curToken} // 1. 创建一个新的ImplStatement节点，记录当前Token
+
+	if !p.expectPeek(token.IDENT) { // 2. 期待下一个Token是枚举名称
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) { // 3. 期待下一个Token是左大括号
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) { // 4. 循环解析方法，直到遇到右大括号
+		if !p.expectPeek(token.FUNCTION) { // 4.1. 每个方法都以fn开头
+			return nil
+		}
+		fnToken := p.curToken
+		method, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+		if !ok {
+			return nil
+		}
+		if method.Name == "" { // 4.2. 方法必须有名称
+			p.errorAt(fnToken, "method in impl %s must have a name", stmt.Name.Value)
+			return nil
+		}
+		if len(method.Parameters) == 0 { // 4.3. 第一个形参接收调用方法的枚举值
+			p.errorAt(fnToken, "method %s must take a receiver parameter", method.Name)
+			return nil
+		}
+		stmt.Methods = append(stmt.Methods, method)
+
+		if p.peekTokenIs(token.SEMICOLON) { // 4.4. 方法之间可以用分号分隔
+			p.nextToken()
+		}
+	}
+	p.nextToken() // 5. 前进到右大括号
+
+	if p.peekTokenIs(token.SEMICOLON) { // 6. 如果下一个Token是分号
+		p.nextToken() // 6.1. 前进到分号
+	}
+
+	return stmt // 7. 返回解析后的ImplStatement节点
+}
+
+// parseFunctionStatement 解析以fn开头的语句，具名函数作为声明，其余作为表达式语句
+func (p *Parser) parseFunctionStatement() ast.Statement {
+	stmt := p.parseExpressionStatement() // 1. 先按表达式语句解析
+
+	if fl, ok := stmt.Expression.(*ast.FunctionLiteral); ok && fl.Name != "" { // 2. 单独出现的具名函数即为函数声明
+		return &ast.FunctionStatement{Token: fl.Token, Function: fl}
+	}
+
+	return stmt // 3. 否则保持为表达式语句，例如立即调用的匿名函数
+}
+
+// parseSelectStatement 解析select语句，例如 select { case v = recv(ch) { v } case send(ch, 1) { 0 } default { -1 } }
+func (p *Parser) parseSelectStatement() ast.Statement {
+	stmt := &ast.SelectStatement{Token: p.curToken} // 1. 创建一个新的SelectStatement节点，记录当前Token
+
+	if !p.expectPeek(token.LBRACE) { // 2. 期待左大括号
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) { // 3. 逐个解析分支，直到右大括号
+		switch {
+		case p.peekTokenIs(token.CASE): // 3.1. 解析通信分支
+			p.nextToken()
+			sc := p.parseSelectCase()
+			if sc == nil {
+				return nil
+			}
+			stmt.Cases = append(stmt.Cases, sc)
+		case p.peekTokenIs(token.DEFAULT) && stmt.Default == nil: // 3.2. 解析唯一的default分支
+			p.nextToken()
+			if !p.expectPeek(token.LBRACE) {
+				return nil
+			}
+			stmt.Default = p.parseBlockStatement()
+		default: // 3.3. 其他Token都是错误
+			p.errorAt(p.peekToken, "expected case or default in select, got %s instead", p.peekToken.Type)
+			return nil
+		}
+	}
+	p.nextToken() // 4. 前进到右大括号
+
+	if p.peekTokenIs(token.SEMICOLON) { // 5. 如果下一个Token是分号
+		p.nextToken() // 5.1. 前进到分号
+	}
+
+	return stmt // 6. 返回解析后的SelectStatement节点
+}
+
+// parseSelectCase 解析select语句的一个通信分支，当前Token为case
+func (p *Parser) parseSelectCase() *ast.SelectCase {
+	sc := &ast.SelectCase{Token: p.curToken} // 1. 创建一个新的SelectCase节点，记录当前Token
+
+	p.nextToken()                                                 // 2. 前进到分支的第一个Token
+	if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.ASSIGN) { // 3. 解析可选的 "v =" 绑定
+		sc.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.nextToken()
+		p.nextToken()
+	}
+
+	call, ok := p.parseExpression(LOWEST).(*ast.CallExpression) // 4. 通信操作必须是 recv(ch) 或 send(ch, value)
+	if !ok || !isCommunication(call) || (sc.Name != nil && call.Function.String() != "recv") {
+		p.errorAt(sc.Token, "select case must be recv(ch), v = recv(ch) or send(ch, value)")
+		return nil
+	}
+	sc.Comm = call
+
+	if !p.expectPeek(token.LBRACE) { // 5. 期待分支的语句块
+		return nil
+	}
+	sc.Body = p.parseBlockStatement()
+
+	return sc // 6. 返回解析后的SelectCase节点
+}
+
+// isCommunication 判断调用是否为select可以等待的通信操作
+func isCommunication(call *ast.CallExpression) bool {
+	switch call.Function.String() {
+	case "recv":
+		return len(call.Arguments) == 1 // 1. recv(ch)
+	case "send":
+		return len(call.Arguments) == 2 // 2. send(ch, value)
+	default:
+		return false
+	}
+}
+
+// parseImportStatement 解析import语句，省略as时以文件名（不含扩展名）作为模块名
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken} // 1. 创建一个新的ImportStatement节点，记录当前Token
+
+	if !p.expectPeek(token.STRING) { // 2. 期待模块路径字符串
+		return nil
+	}
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.AS) { // 3. 解析可选的模块别名
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	} else { // 4. 默认使用文件名作为模块名
+		name := strings.TrimSuffix(path.Base(stmt.Path.Value), path.Ext(stmt.Path.Value))
+		stmt.Alias = &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 5. 如果下一个Token是分号
+		p.nextToken() // 5.1. 前进到分号
+	}
+
+	return stmt // 6. 返回解析后的ImportStatement节点
+}
+
+// parseExportStatement 解析export声明，只能导出let、fn和enum声明
+func (p *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{Token: p.curToken} // 1. 创建一个新的ExportStatement节点，记录当前Token
+
+	p.nextToken() // 2. 前进到被导出的声明
+
+	switch decl := p.parseStatement().(type) { // 3. 解析被导出的声明
+	case *ast.LetStatement, *ast.FunctionStatement, *ast.EnumStatement:
+		stmt.Statement = decl
+	case nil:
+		return nil
+	default:
+		p.errorAt(stmt.Token, "export must be followed by a let, fn or enum declaration")
+		return nil
+	}
+
+	return stmt // 4. 返回解析后的ExportStatement节点
+}
+
+// parseThrowStatement 解析throw语句
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{Token: p.curToken} // 1. 创建一个新的ThrowStatement节点，记录当前Token
+
+	p.nextToken()                          // 2. 前进到被抛出的表达式
+	stmt.Value = p.parseExpression(LOWEST) // 3. 解析被抛出的表达式
+	if stmt.Value == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 4. 如果下一个Token是分号
+		p.nextToken() // 4.1. 前进到分号
+	}
+
+	return stmt // 5. 返回解析后的ThrowStatement节点
+}
+
+// parseDeferStatement 解析defer语句，defer之后必须是一次调用
+func (p *Parser) parseDeferStatement() ast.Statement {
+	stmt := &ast.DeferStatement{Token: p.curToken} // 1. 创建一个新的DeferStatement节点，记录当前Token
+
+	p.nextToken() // 2. 前进到被推迟的调用
+
+	call, ok := p.parseExpression(LOWEST).(*ast.CallExpression) // 3. 解析调用表达式
+	if !ok {
+		p.errorAt(stmt.Token, "expression in defer must be a function call")
+		return nil
+	}
+	stmt.Call = call
+
+	if p.peekTokenIs(token.SEMICOLON) { // 4. 如果下一个Token是分号
+		p.nextToken() // 4.1. 前进到分号
+	}
+
+	return stmt // 5. 返回解析后的DeferStatement节点
+}
+
+// parseTryStatement 解析try语句，例如 try { ... } catch (e) { ... } finally { ... }
+func (p *Parser) parseTryStatement() ast.Statement {
+	stmt := &ast.TryStatement{Token: p.curToken} // 1. 创建一个新的TryStatement节点，记录当前Token
+
+	if !p.expectPeek(token.LBRACE) { // 2. 期待try后紧跟语句块
+		return nil
+	}
+	stmt.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) { // 3. 解析可选的catch部分
+		p.nextToken()
+		if p.peekTokenIs(token.LPAREN) { // 3.1. 解析可选的错误参数
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			stmt.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+		if !p.expectPeek(token.LBRACE) { // 3.2. 期待catch语句块
+			return nil
+		}
+		stmt.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) { // 4. 解析可选的finally部分
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		stmt.Finally = p.parseBlockStatement()
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil { // 5. catch和finally至少需要一个
+		p.errorAt(stmt.Token, "expected catch or finally after try block")
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) { // 6. 如果下一个Token是分号
+		p.nextToken() // 6.1. 前进到分号
+	}
+
+	return stmt // 7. 返回解析后的TryStatement节点
+}
+
+// parseBlockStatement 解析由大括号包围的语句块，当前Token为左大括号
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken} // 1. 创建一个新的BlockStatement节点，记录当前Token
+	block.Statements = []ast.Statement{}
+
+	p.nextToken() // 2. 跳过左大括号
+
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) { // 3. 逐条解析语句，直到右大括号
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(token.RBRACE) { // 4. 如果直到结尾都没有右大括号，记录错误
+		p.errorAt(p.curToken, "expected next token to be %s, got %s instead", token.RBRACE, p.curToken.Type)
+	}
+
+	return block // 5. 返回解析后的BlockStatement节点
+}
+
+// parseIdentifierList 解析以逗号分隔的标识符列表，当前Token为左括号，解析至end结束
+func (p *Parser) parseIdentifierList(end token.TokenType) []*ast.Identifier {
+	identifiers := []*ast.Identifier{}
+
+	if p.peekTokenIs(end) { // 1. 如果列表为空，直接跳过结束Token
+		p.nextToken()
+		return identifiers
+	}
+
+	if !p.expectPeek(token.IDENT) { // 2. 读取第一个标识符
+		return nil
+	}
+	identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+	for p.peekTokenIs(token.COMMA) { // 3. 逐个读取后续以逗号分隔的标识符
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+	}
+
+	if !p.expectPeek(end) { // 4. 期待列表以结束Token收尾
+		return nil
+	}
+
+	return identifiers // 5. 返回标识符列表
 }
 
 // parseExpressionStatement 解析表达式语句
@@ -207,25 +799,36 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 
 // parseExpression 解析表达式，根据当前Token的优先级决定解析顺序
 func (p *Parser) parseExpression(precedence int) ast.Expression {
-	prefix := p.prefixParseFns[p.curToken.Type] // 1. 获取当前Token对应的前缀解析函数
-	if prefix == nil {                          // 2. 如果没有对应的前缀解析函数
-		p.noPrefixParseFnError(p.curToken.Type) // 2.1. 记录错误
-		return nil                              // 2.2. 返回nil
+	if p.curTokenIs(token.ILLEGAL) { // 1. 词法分析器无法识别的输入
+		p.errorAt(p.curToken, "illegal token: %s", p.curToken.Literal)
+		return nil
 	}
-	leftExp := prefix() // 3. 调用前缀解析函数，获取左表达式
 
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() { // 4. 循环处理中缀表达式
-		infix := p.infixParseFns[p.peekToken.Type] // 4.1. 获取下一个Token对应的中缀解析函数
-		if infix == nil {                          // 4.2. 如果没有对应的中缀解析函数
-			return leftExp // 4.2.1. 返回当前的左表达式
+	prefix := p.prefixParseFns[p.curToken.Type] // 2. 获取当前Token对应的前缀解析函数
+	if prefix == nil {                          // 3. 如果没有对应的前缀解析函数
+		p.noPrefixParseFnError(p.curToken.Type) // 3.1. 记录错误
+		return nil                              // 3.2. 返回nil
+	}
+	leftExp := prefix() // 4. 调用前缀解析函数，获取左表达式
+
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() { // 5. 循环处理后缀和中缀表达式
+		if postfix := p.postfixParseFns[p.peekToken.Type]; postfix != nil { // 5.1. 后缀操作符不需要右侧表达式
+			p.nextToken()
+			leftExp = postfix(leftExp)
+			continue
+		}
+
+		infix := p.infixParseFns[p.peekToken.Type] // 5.2. 获取下一个Token对应的中缀解析函数
+		if infix == nil {                          // 5.3. 如果没有对应的中缀解析函数
+			return leftExp // 5.3.1. 返回当前的左表达式
 		}
 
-		p.nextToken() // 5. 前进到下一个Token，准备解析中缀表达式
+		p.nextToken() // 6. 前进到下一个Token，准备解析中缀表达式
 
-		leftExp = infix(leftExp) // 6. 解析中缀表达式，更新左表达式
+		leftExp = infix(leftExp) // 7. 解析中缀表达式，更新左表达式
 	}
 
-	return leftExp // 7. 返回解析后的表达式
+	return leftExp // 8. 返回解析后的表达式
 }
 
 // parseIdentifier 解析标识符，返回Identifier节点
@@ -242,9 +845,8 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64) // 2. 将字面量转换为整数
 	if err != nil {                                           // 3. 如果转换失败
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal) // 3.1. 构建错误消息
-		p.errors = append(p.errors, msg)                                        // 3.2. 记录错误
-		return nil                                                              // 3.3. 返回nil
+		p.errorAt(p.curToken, "could not parse %q as integer", p.curToken.Literal) // 3.1. 记录错误
+		return nil                                                                 // 3.2. 返回nil
 	}
 
 	lit.Value = value // 4. 设置整数值
@@ -280,12 +882,893 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression // 7. 返回解析后的InfixExpression节点
 }
 
+// parseBoolean 解析布尔字面量，返回Boolean节点
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{
+		Token: p.curToken,               // 1. 当前Token
+		Value: p.curTokenIs(token.TRUE), // 2. 根据Token类型确定布尔值
+	}
+}
+
+// parseFunctionLiteral 解析函数字面量，例如 fn(x) { x }、fn add(x, y) { x + y } 或 fn* count(n) { yield n; }
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.curToken} // 1. 创建一个新的FunctionLiteral节点，记录当前Token
+
+	if p.peekTokenIs(token.ASTERISK) { // 2. fn* 表示生成器函数
+		p.nextToken()
+		lit.IsGenerator = true
+	}
+
+	if p.peekTokenIs(token.IDENT) { // 3. 解析可选的函数名
+		p.nextToken()
+		lit.Name = p.curToken.Literal
+	}
+
+	if p.peekTokenIs(token.LT) { // 3.1. 解析可选的类型参数，例如 fn first<T>(xs: [T]) -> T
+		p.nextToken()
+		if lit.TypeParams = p.parseTypeParams(); lit.TypeParams == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LPAREN) { // 4. 期待形参列表
+		return nil
+	}
+	if !p.parseFunctionParameters(lit) { // 5. 解析形参列表
+		return nil
+	}
+
+	if p.peekTokenIs(token.ARROW) { // 5.1. 解析可选的返回值类型标注
+		p.nextToken()
+		p.nextToken()
+		if lit.ReturnType = p.parseTypeExpression(); lit.ReturnType == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) { // 6. 期待函数体
+		return nil
+	}
+	lit.Body = p.parseBlockStatement() // 7. 解析函数体
+
+	return lit // 8. 返回解析后的FunctionLiteral节点
+}
+
+// parseFunctionParameters 解析函数的形参列表，当前Token为左括号。
+// 形参可以用 "= 表达式" 声明默认值，有默认值的形参之后不能再出现没有默认值的形参；最后一个形参可以是以 "..." 开头的可变参数
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) bool {
+	lit.Parameters = []*ast.Identifier{}
+	lit.Defaults = []ast.Expression{}
+	lit.ParamTypes = []ast.TypeExpression{}
+	hasDefault := false
+	seen := map[string]bool{} // 已经声明的形参名，同名形参会互相覆盖，因此报错
+
+	if p.peekTokenIs(token.RPAREN) { // 1. 如果列表为空，直接跳过右括号
+		p.nextToken()
+		return true
+	}
+
+	for {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) { // 2. 可变参数之后只能是右括号
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			lit.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if seen[lit.Rest.Value] {
+				p.errorAt(p.curToken, "duplicate parameter %s", lit.Rest.Value)
+				return false
+			}
+			if p.peekTokenIs(token.COLON) { // 2.1. 可变参数的类型标注是收集实参的数组的类型
+				p.nextToken()
+				p.nextToken()
+				if lit.RestType = p.parseTypeExpression(); lit.RestType == nil {
+					return false
+				}
+			}
+			if !p.peekTokenIs(token.RPAREN) {
+				p.errorAt(p.peekToken, "variadic parameter %s must be the last parameter", lit.Rest.Value)
+				return false
+			}
+			break
+		}
+
+		if !p.curTokenIs(token.IDENT) { // 3. 普通形参必须是标识符
+			p.errorAt(p.curToken, "expected parameter name, got %s instead", p.curToken.Type)
+			return false
+		}
+		param := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[param.Value] {
+			p.errorAt(p.curToken, "duplicate parameter %s", param.Value)
+			return false
+		}
+		seen[param.Value] = true
+
+		var typ ast.TypeExpression
+		if p.peekTokenIs(token.COLON) { // 3.0. 解析可选的类型标注
+			p.nextToken()
+			p.nextToken()
+			if typ = p.parseTypeExpression(); typ == nil {
+				return false
+			}
+		}
+
+		var dflt ast.Expression
+		if p.peekTokenIs(token.ASSIGN) { // 3.1. 解析可选的默认值
+			p.nextToken()
+			p.nextToken()
+			dflt = p.parseExpression(LOWEST)
+			hasDefault = true
+		} else if hasDefault {
+			p.errorAt(param.Token, "parameter %s without default follows parameter with default", param.Value)
+			return false
+		}
+		lit.Parameters = append(lit.Parameters, param)
+		lit.Defaults = append(lit.Defaults, dflt)
+		lit.ParamTypes = append(lit.ParamTypes, typ)
+
+		if !p.peekTokenIs(token.COMMA) { // 4. 没有逗号时形参列表结束
+			break
+		}
+		p.nextToken()
+	}
+
+	return p.expectPeek(token.RPAREN) // 5. 期待列表以右括号收尾
+}
+
+// parseTypeExpression 解析类型标注，当前Token为类型的第一个Token，结束时当前Token为类型的最后一个Token。
+// 类型可以是名字（int、any、枚举名等）、带类型实参的泛型枚举 Pair<A, B>、[T]、{K: V}、#{T}、(T1, T2) 或 fn(T1, T2) -> R
+func (p *Parser) parseTypeExpression() ast.TypeExpression {
+	switch tok := p.curToken; tok.Type {
+	case token.IDENT, token.NIL: // 1. 以名字表示的类型
+		named := &ast.NamedType{Token: tok, Name: tok.Literal}
+		if tok.Type == token.IDENT && p.peekTokenIs(token.LT) { // 1.1. 解析可选的类型实参
+			p.nextToken()
+			for {
+				p.nextToken()
+				arg := p.parseTypeExpression()
+				if arg == nil {
+					return nil
+				}
+				named.Arguments = append(named.Arguments, arg)
+				if !p.peekTokenIs(token.COMMA) {
+					break
+				}
+				p.nextToken()
+			}
+			if !p.expectPeek(token.GT) {
+				return nil
+			}
+		}
+		return named
+
+	case token.LBRACKET: // 2. 数组类型
+		p.nextToken()
+		elem := p.parseTypeExpression()
+		if elem == nil || !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		return &ast.ArrayType{Token: tok, Element: elem}
+
+	case token.LBRACE: // 3. 哈希类型
+		p.nextToken()
+		key := p.parseTypeExpression()
+		if key == nil || !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken()
+		value := p.parseTypeExpression()
+		if value == nil || !p.expectPeek(token.RBRACE) {
+			return nil
+		}
+		return &ast.HashType{Token: tok, Key: key, Value: value}
+
+	case token.SET_LBRACE: // 4. 集合类型
+		p.nextToken()
+		elem := p.parseTypeExpression()
+		if elem == nil || !p.expectPeek(token.RBRACE) {
+			return nil
+		}
+		return &ast.SetType{Token: tok, Element: elem}
+
+	case token.LPAREN: // 5. 元组类型
+		elements := p.parseTypeList()
+		if elements == nil {
+			return nil
+		}
+		return &ast.TupleType{Token: tok, Elements: elements}
+
+	case token.FUNCTION: // 6. 函数类型
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+		ft := &ast.FunctionType{Token: tok}
+		if ft.Parameters = p.parseTypeList(); ft.Parameters == nil {
+			return nil
+		}
+		if p.peekTokenIs(token.ARROW) {
+			p.nextToken()
+			p.nextToken()
+			if ft.Return = p.parseTypeExpression(); ft.Return == nil {
+				return nil
+			}
+		}
+		return ft
+
+	default:
+		p.errorAt(tok, "expected type, got %s instead", tok.Type)
+		return nil
+	}
+}
+
+// parseTypeList 解析括号中以逗号分隔的类型列表，当前Token为左括号，结束时当前Token为右括号
+func (p *Parser) parseTypeList() []ast.TypeExpression {
+	list := []ast.TypeExpression{}
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		typ := p.parseTypeExpression()
+		if typ == nil {
+			return nil
+		}
+		list = append(list, typ)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return list
+}
+
+// parseSpawnExpression 解析spawn表达式，spawn之后必须是一次调用
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	exp := &ast.SpawnExpression{Token: p.curToken} // 1. 创建一个新的SpawnExpression节点，记录当前Token
+
+	p.nextToken() // 2. 前进到被启动的调用
+
+	call, ok := p.parseExpression(PREFIX).(*ast.CallExpression) // 3. 解析调用表达式
+	if !ok {
+		p.errorAt(exp.Token, "expression in spawn must be a function call")
+		return nil
+	}
+	exp.Call = call
+
+	return exp // 4. 返回解析后的SpawnExpression节点
+}
+
+// parseStringLiteral 解析字符串字面量，转义序列在此时解析；包含 "${...}" 插值时返回InterpolatedString节点
+func (p *Parser) parseStringLiteral() ast.Expression {
+	if interpolationStart(p.curToken.Literal, 0) < 0 { // 1. 没有插值的普通字符串
+		value, err := lexer.Unescape(p.curToken.Literal)
+		if err != nil {
+			p.errorAt(p.curToken, "invalid string literal: %s", err)
+			return nil
+		}
+		return &ast.StringLiteral{Token: p.curToken, Value: value}
+	}
+	return p.parseInterpolatedString() // 2. 解析插值字符串
+}
+
+// interpolationStart 返回字符串内容 lit 中从下标 from 开始的第一个未被转义的 "${" 的下标，没有时返回 -1
+func interpolationStart(lit string, from int) int {
+	for i := from; i < len(lit)-1; i++ {
+		switch {
+		case lit[i] == '\\':
+			i++ // 跳过被转义的字符
+		case lit[i] == '$' && lit[i+1] == '{':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseBytesLiteral 解析字节串字面量，转义序列在此时解析
+func (p *Parser) parseBytesLiteral() ast.Expression {
+	value, err := lexer.Unescape(p.curToken.Literal)
+	if err != nil {
+		p.errorAt(p.curToken, "invalid bytes literal: %s", err)
+		return nil
+	}
+	return &ast.BytesLiteral{Token: p.curToken, Value: []byte(value)}
+}
+
+// parseCharLiteral 解析字符字面量，转义后必须恰好是一个 UTF-8 编码的字符
+func (p *Parser) parseCharLiteral() ast.Expression {
+	value, err := lexer.Unescape(p.curToken.Literal)
+	if err != nil {
+		p.errorAt(p.curToken, "invalid char literal: %s", err)
+		return nil
+	}
+	ch, size := utf8.DecodeRuneInString(value)
+	if value == "" || size != len(value) || (ch == utf8.RuneError && size == 1) {
+		p.errorAt(p.curToken, "char literal must contain exactly one character, got '%s'", p.curToken.Literal)
+		return nil
+	}
+	return &ast.CharLiteral{Token: p.curToken, Value: ch}
+}
+
+// parseRegexLiteral 解析正则表达式字面量，模式在评估时才编译，这里只检查标志
+func (p *Parser) parseRegexLiteral() ast.Expression {
+	lit := p.curToken.Literal
+	end := strings.LastIndex(lit, "\"") // 1. 最后一个引号之后是标志
+	exp := &ast.RegexLiteral{Token: p.curToken, Pattern: lit[1:end], Flags: lit[end+1:]}
+
+	for _, f := range exp.Flags { // 2. 检查标志是否合法
+		if !strings.ContainsRune("imsU", f) {
+			p.errorAt(p.curToken, "unknown regex flag %c, want one of i, m, s and U", f)
+			return nil
+		}
+	}
+	return exp
+}
+
+// parseInterpolatedString 把字符串拆分为文本片段和插值表达式，插值表达式由嵌套的Parser解析
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	lit := p.curToken.Literal
+	exp := &ast.InterpolatedString{Token: p.curToken} // 1. 创建一个新的InterpolatedString节点，记录当前Token
+
+	text := func(s string) *ast.StringLiteral { // 文本片段的字面量保留原始内容，值是转义后的内容
+		value, err := lexer.Unescape(s)
+		if err != nil {
+			p.errorAt(p.curToken, "invalid string literal: %s", err)
+			return nil
+		}
+		return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: s}, Value: value}
+	}
+
+	for i := 0; i < len(lit); { // 2. 依次查找每个插值
+		start := interpolationStart(lit, i)
+		if start < 0 { // 2.1. 剩余部分都是文本
+			part := text(lit[i:])
+			if part == nil {
+				return nil
+			}
+			exp.Parts = append(exp.Parts, part)
+			break
+		}
+		if start > i { // 2.2. 插值之前的文本
+			part := text(lit[i:start])
+			if part == nil {
+				return nil
+			}
+			exp.Parts = append(exp.Parts, part)
+		}
+
+		end := lexer.InterpolationEnd(lit, start+1) // 2.3. 使用与词法分析相同的规则找到插值的结束位置
+		if end < 0 {
+			p.errorAt(p.stringPosition(start), "unterminated string interpolation")
+			return nil
+		}
+
+		inner := p.parseInterpolation(lit[start+2:end], p.stringPosition(start+2)) // 2.4. 解析插值表达式
+		if inner == nil {
+			return nil
+		}
+		exp.Parts = append(exp.Parts, inner)
+		i = end + 1
+	}
+
+	return exp // 3. 返回解析后的InterpolatedString节点
+}
+
+// stringPosition 返回当前字符串Token的内容中第offset个字节所在的行列，用于报告插值中的错误
+func (p *Parser) stringPosition(offset int) token.Token {
+	tok := token.Token{Line: p.curToken.Line, Column: p.curToken.Column + 1} // 1. 内容从左引号之后开始
+	if tok.Line == 0 {
+		return tok
+	}
+	for _, ch := range p.curToken.Literal[:offset] { // 2. 按字符推进行列
+		if ch == '\n' {
+			tok.Line++
+			tok.Column = 1
+		} else {
+			tok.Column++
+		}
+	}
+	return tok
+}
+
+// parseInterpolation 使用嵌套的Parser解析一个插值表达式，嵌套的词法分析器从插值所在的行列开始计数
+func (p *Parser) parseInterpolation(source string, pos token.Token) ast.Expression {
+	nested := New(lexer.NewAt(source, pos.Line, pos.Column)) // 1. 为插值表达式创建嵌套的Parser
+
+	var exp ast.Expression
+	if nested.curTokenIs(token.EOF) { // 2. 插值表达式不能为空
+		nested.errorAt(pos, "empty expression")
+	} else {
+		exp = nested.parseExpression(LOWEST) // 3. 解析插值表达式
+		if !nested.peekTokenIs(token.EOF) {  // 4. 插值中只能有一个表达式
+			nested.errorAt(nested.peekToken, "expected end of expression, got %s instead", nested.peekToken.Type)
+		}
+	}
+
+	for _, msg := range nested.Errors() { // 5. 记录嵌套Parser的错误，它们已经带有绝对位置
+		p.errors = append(p.errors, "in string interpolation: "+msg)
+	}
+	if len(nested.Errors()) != 0 {
+		return nil
+	}
+
+	return exp // 6. 返回解析后的表达式
+}
+
+// parseMemberExpression 解析成员访问表达式，返回MemberExpression节点
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object} // 1. 创建MemberExpression节点，记录被访问的对象
+	exp.Optional = p.curTokenIs(token.OPTIONAL_DOT)
+
+	if token.LookupIdent(p.peekToken.Literal) == token.IDENT && !p.peekTokenIs(token.IDENT) { // 2. 期待成员名，关键字也可以作为成员名，例如 re.match
+		p.peekError(token.IDENT)
+		return nil
+	}
+	p.nextToken()
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp // 3. 返回解析后的MemberExpression节点
+}
+
+// parseIndexExpression 解析下标表达式，返回IndexExpression节点；中括号内出现冒号时解析为SliceExpression节点
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	tok := p.curToken
+	optional := p.curTokenIs(token.OPTIONAL_LBRACKET)
+
+	p.nextToken() // 1. 前进到下标
+	var index ast.Expression
+	if !p.curTokenIs(token.COLON) {
+		index = p.parseExpression(LOWEST) // 2. 解析下标或切片的起始下标
+		if !p.peekTokenIs(token.COLON) {  // 3. 没有冒号时是普通的下标表达式
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: tok, Left: left, Index: index, Optional: optional}
+		}
+		p.nextToken() // 3.1. 前进到冒号
+	}
+
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: index, Optional: optional} // 4. 创建SliceExpression节点
+	p.nextToken()
+	if !p.curTokenIs(token.COLON) && !p.curTokenIs(token.RBRACKET) { // 5. 解析可选的结束下标
+		exp.End = p.parseExpression(LOWEST)
+		p.nextToken()
+	}
+	if p.curTokenIs(token.COLON) { // 6. 解析可选的步长
+		p.nextToken()
+		if !p.curTokenIs(token.RBRACKET) {
+			exp.Step = p.parseExpression(LOWEST)
+			p.nextToken()
+		}
+	}
+	if !p.curTokenIs(token.RBRACKET) { // 7. 切片必须以右中括号结束
+		p.errorAt(p.curToken, "expected %s to close slice, got %s instead", token.RBRACKET, p.curToken.Type)
+		return nil
+	}
+
+	return exp // 8. 返回解析后的SliceExpression节点
+}
+
+// parseArrayLiteral 解析数组字面量，返回ArrayLiteral节点
+// 第一个元素之后紧跟 for 时解析为数组推导式，例如 [x * x for x in xs if x % 2 == 0]
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken} // 1. 创建一个新的ArrayLiteral节点，记录当前Token
+	if p.peekTokenIs(token.RBRACKET) {            // 2. 空数组
+		p.nextToken()
+		array.Elements = []ast.Expression{}
+		return array
+	}
+
+	p.nextToken()
+	first := p.parseExpression(LOWEST) // 3. 解析第一个元素
+
+	if p.peekTokenIs(token.FOR) { // 4. 数组推导式
+		exp := &ast.ListComprehension{Token: array.Token, Element: first}
+		if exp.Clause = p.parseComprehensionClause(); exp.Clause == nil {
+			return nil
+		}
+		if !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		return exp
+	}
+
+	array.Elements = []ast.Expression{first}
+	for p.peekTokenIs(token.COMMA) { // 5. 逐个解析后续以逗号分隔的元素
+		p.nextToken()
+		p.nextToken()
+		array.Elements = append(array.Elements, p.parseExpression(LOWEST))
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return array // 6. 返回解析后的ArrayLiteral节点
+}
+
+// parseComprehensionClause 解析推导式的 for 子句，当前Token为 for 之前的表达式，结束时当前Token为子句的最后一个Token
+func (p *Parser) parseComprehensionClause() *ast.ComprehensionClause {
+	p.nextToken()
+	clause := &ast.ComprehensionClause{Token: p.curToken} // 1. 记录 for
+
+	if p.peekTokenIs(token.LPAREN) { // 2. 解析循环变量，可以解构元组
+		p.nextToken()
+		clause.Names = p.parseIdentifierList(token.RPAREN)
+		if len(clause.Names) == 0 {
+			p.errorAt(clause.Token, "destructuring in comprehension needs at least one name")
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		clause.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+	clause.Iterable = p.parseExpression(LOWEST) // 3. 解析被遍历的对象
+
+	if p.peekTokenIs(token.IF) { // 4. 解析可选的过滤条件
+		p.nextToken()
+		p.nextToken()
+		clause.Condition = p.parseExpression(LOWEST)
+	}
+
+	return clause
+}
+
+// parseSetLiteral 解析集合字面量，例如 #{1, 2, 3}
+func (p *Parser) parseSetLiteral() ast.Expression {
+	set := &ast.SetLiteral{Token: p.curToken}          // 1. 创建一个新的SetLiteral节点，记录当前Token
+	set.Elements = p.parseExpressionList(token.RBRACE) // 2. 解析以逗号分隔的元素
+	if set.Elements == nil {
+		return nil
+	}
+	return set // 3. 返回解析后的SetLiteral节点
+}
+
+// parseRangeExpression 解析区间表达式，终点之后可以用 step 指定步长，例如 1..=10 step 2
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{Token: p.curToken, Start: start} // 1. 创建RangeExpression节点，记录起点
+	exp.Inclusive = p.curTokenIs(token.DOTDOT_EQ)
+
+	p.nextToken()
+	exp.End = p.parseExpression(RANGE) // 2. 解析终点
+
+	if p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "step" { // 3. step 只在区间终点之后才有特殊含义
+		p.nextToken()
+		p.nextToken()
+		exp.Step = p.parseExpression(RANGE)
+	}
+
+	return exp // 4. 返回解析后的RangeExpression节点
+}
+
+// parseForStatement 解析for循环，例如 for x in 1..10 { ... }
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.curToken} // 1. 创建一个新的ForStatement节点，记录当前Token
+
+	if !p.expectPeek(token.IDENT) { // 2. 解析循环变量
+		return nil
+	}
+	stmt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST) // 3. 解析被遍历的对象
+
+	if !p.expectPeek(token.LBRACE) { // 4. 解析循环体
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLON) { // 5. 如果下一个Token是分号
+		p.nextToken() // 5.1. 前进到分号
+	}
+
+	return stmt // 6. 返回解析后的ForStatement节点
+}
+
+// parseSpreadExpression 解析展开表达式，返回SpreadExpression节点
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	exp := &ast.SpreadExpression{Token: p.curToken} // 1. 创建一个新的SpreadExpression节点，记录当前Token
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST) // 2. 解析被展开的表达式
+
+	return exp // 3. 返回解析后的SpreadExpression节点
+}
+
+// parseHashLiteral 解析哈希字面量，例如 {"a": 1, ...m}；第一项之后紧跟 for 时解析为哈希推导式
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken} // 1. 创建一个新的HashLiteral节点，记录当前Token
+	hash.Entries = []*ast.HashEntry{}
+
+	for !p.peekTokenIs(token.RBRACE) { // 2. 逐项解析，直到右大括号
+		p.nextToken()
+		entry := &ast.HashEntry{Key: p.parseExpression(LOWEST)}
+		if _, ok := entry.Key.(*ast.SpreadExpression); !ok { // 2.1. 普通项需要 "键: 值"
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			entry.Value = p.parseExpression(LOWEST)
+		}
+
+		if len(hash.Entries) == 0 && entry.Value != nil && p.peekTokenIs(token.FOR) { // 2.2. 第一项之后紧跟 for 时是哈希推导式
+			exp := &ast.HashComprehension{Token: hash.Token, Key: entry.Key, Value: entry.Value}
+			if exp.Clause = p.parseComprehensionClause(); exp.Clause == nil {
+				return nil
+			}
+			if !p.expectPeek(token.RBRACE) {
+				return nil
+			}
+			return exp
+		}
+		hash.Entries = append(hash.Entries, entry)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) { // 2.3. 各项以逗号分隔
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) { // 3. 期待右大括号
+		return nil
+	}
+
+	return hash // 4. 返回解析后的HashLiteral节点
+}
+
+// parseMatchExpression 解析 match 表达式，例如 match s { case Circle(r) { r * r } case Empty { 0 } default { -1 } }
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken} // 1. 创建一个新的MatchExpression节点，记录当前Token
+
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST) // 2. 解析被匹配的值
+
+	if !p.expectPeek(token.LBRACE) { // 3. 期待左大括号
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) { // 4. 逐个解析分支，直到右大括号
+		switch {
+		case p.peekTokenIs(token.CASE): // 4.1. 解析变体分支
+			p.nextToken()
+			mc := p.parseMatchCase()
+			if mc == nil {
+				return nil
+			}
+			exp.Cases = append(exp.Cases, mc)
+		case p.peekTokenIs(token.DEFAULT) && exp.Default == nil: // 4.2. 解析唯一的default分支
+			p.nextToken()
+			if !p.expectPeek(token.LBRACE) {
+				return nil
+			}
+			exp.Default = p.parseBlockStatement()
+		default: // 4.3. 其他Token都是错误
+			p.errorAt(p.peekToken, "expected case or default in match, got %s instead", p.peekToken.Type)
+			return nil
+		}
+	}
+	p.nextToken() // 5. 前进到右大括号
+
+	return exp // 6. 返回解析后的MatchExpression节点
+}
+
+// parseMatchCase 解析match表达式的一个分支，当前Token为case
+func (p *Parser) parseMatchCase() *ast.MatchCase {
+	mc := &ast.MatchCase{Token: p.curToken} // 1. 创建一个新的MatchCase节点，记录当前Token
+
+	if !p.expectPeek(token.IDENT) { // 2. 期待变体名称
+		return nil
+	}
+	mc.Variant = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.LPAREN) { // 3. 解析可选的字段绑定列表
+		p.nextToken()
+		if mc.Names = p.parseIdentifierList(token.RPAREN); mc.Names == nil {
+			return nil
+		}
+		seen := map[string]bool{}
+		for _, name := range mc.Names { // 3.1. 除 _ 以外的变量名不能重复
+			if seen[name.Value] {
+				p.errorAt(name.Token, "duplicate name %s in pattern", name.Value)
+				return nil
+			}
+			seen[name.Value] = name.Value != "_"
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) { // 4. 期待分支的语句块
+		return nil
+	}
+	mc.Body = p.parseBlockStatement()
+
+	return mc // 5. 返回解析后的MatchCase节点
+}
+
+// parseIfExpression 解析条件表达式，else 之后可以是语句块或另一个 if 表达式
+func (p *Parser) parseIfExpression() ast.Expression {
+	exp := &ast.IfExpression{Token: p.curToken} // 1. 创建一个新的IfExpression节点，记录当前Token
+
+	p.nextToken()
+	exp.Condition = p.parseExpression(LOWEST) // 2. 解析条件
+
+	if !p.expectPeek(token.LBRACE) { // 3. 解析条件成立时的语句块
+		return nil
+	}
+	exp.Consequence = p.parseBlockStatement()
+
+	if !p.peekTokenIs(token.ELSE) { // 4. 没有 else 分支
+		return exp
+	}
+	p.nextToken()
+
+	if p.peekTokenIs(token.IF) { // 5. else if 包装为只包含一个 if 表达式的语句块
+		p.nextToken()
+		tok := p.curToken
+		alt := p.parseIfExpression()
+		if alt == nil {
+			return nil
+		}
+		exp.Alternative = &ast.BlockStatement{
+			Token:      tok,
+			Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: alt}},
+		}
+		return exp
+	}
+
+	if !p.expectPeek(token.LBRACE) { // 6. 解析 else 语句块
+		return nil
+	}
+	exp.Alternative = p.parseBlockStatement()
+
+	return exp // 7. 返回解析后的IfExpression节点
+}
+
+// parseNilLiteral 解析空值字面量，返回NilLiteral节点
+func (p *Parser) parseNilLiteral() ast.Expression {
+	return &ast.NilLiteral{Token: p.curToken}
+}
+
+// parseMacroLiteral 解析宏字面量，例如 macro(a, b) { quote(unquote(a) + unquote(b)) }
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken} // 1. 创建一个新的MacroLiteral节点，记录当前Token
+
+	if !p.expectPeek(token.LPAREN) { // 2. 期待形参列表
+		return nil
+	}
+	lit.Parameters = p.parseIdentifierList(token.RPAREN) // 3. 解析形参列表
+	if lit.Parameters == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, param := range lit.Parameters { // 3.1. 同名形参会互相覆盖，因此报错
+		if seen[param.Value] {
+			p.errorAt(param.Token, "duplicate parameter %s", param.Value)
+			return nil
+		}
+		seen[param.Value] = true
+	}
+
+	if !p.expectPeek(token.LBRACE) { // 4. 期待宏体
+		return nil
+	}
+	lit.Body = p.parseBlockStatement() // 5. 解析宏体
+
+	return lit // 6. 返回解析后的MacroLiteral节点
+}
+
+// parseCallExpression 解析调用表达式，返回CallExpression节点
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{Token: p.curToken, Function: function} // 1. 创建CallExpression节点，记录被调用的表达式
+	exp.Arguments = p.parseCallArguments()                            // 2. 解析实参列表
+	return exp                                                        // 3. 返回解析后的CallExpression节点
+}
+
+// parseCallArguments 解析调用的实参列表，关键字实参写作 name: value，必须位于所有位置实参之后
+func (p *Parser) parseCallArguments() []ast.Expression {
+	args := []ast.Expression{}
+
+	if p.peekTokenIs(token.RPAREN) { // 1. 如果列表为空，直接跳过右括号
+		p.nextToken()
+		return args
+	}
+
+	keyword := false
+	for {
+		p.nextToken()
+		if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) { // 2. 解析关键字实参
+			kw := &ast.KeywordArgument{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+			p.nextToken()
+			p.nextToken()
+			kw.Value = p.parseExpression(LOWEST)
+			args = append(args, kw)
+			keyword = true
+		} else { // 3. 解析位置实参
+			if keyword {
+				p.errorAt(p.curToken, "positional argument follows keyword argument")
+				return nil
+			}
+			args = append(args, p.parseExpression(LOWEST))
+		}
+
+		if !p.peekTokenIs(token.COMMA) { // 4. 没有逗号时实参列表结束
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RPAREN) { // 5. 期待列表以右括号收尾
+		return nil
+	}
+
+	return args // 6. 返回实参列表
+}
+
+// parseExpressionList 解析以逗号分隔的表达式列表，解析至end结束
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if p.peekTokenIs(end) { // 1. 如果列表为空，直接跳过结束Token
+		p.nextToken()
+		return list
+	}
+
+	p.nextToken()                                  // 2. 前进到第一个表达式
+	list = append(list, p.parseExpression(LOWEST)) // 3. 解析第一个表达式
+
+	for p.peekTokenIs(token.COMMA) { // 4. 逐个解析后续以逗号分隔的表达式
+		p.nextToken()
+		p.nextToken()
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(end) { // 5. 期待列表以结束Token收尾
+		return nil
+	}
+
+	return list // 6. 返回表达式列表
+}
+
+// parsePostfixExpression 解析后缀表达式，返回PostfixExpression节点
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	return &ast.PostfixExpression{
+		Token:    p.curToken,         // 1. 当前Token
+		Operator: p.curToken.Literal, // 2. 操作符
+		Left:     left,               // 3. 左侧表达式
+	}
+}
+
 // parseGroupedExpression 解析分组表达式（括号内的表达式），返回解析后的表达式节点
+// 括号内出现逗号时解析为元组，例如 (1, 2) 和 (1,)；() 是空元组
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	tok := p.curToken
+	if p.peekTokenIs(token.RPAREN) { // 0. () 是空元组
+		p.nextToken()
+		return &ast.TupleLiteral{Token: tok, Elements: []ast.Expression{}}
+	}
+
 	p.nextToken() // 1. 前进到下一个Token，解析括号内的表达式
 
 	exp := p.parseExpression(LOWEST) // 2. 解析括号内的表达式，优先级最低
 
+	if p.peekTokenIs(token.COMMA) { // 2.1. 出现逗号时解析为元组，允许末尾逗号
+		tuple := &ast.TupleLiteral{Token: tok, Elements: []ast.Expression{exp}}
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			if p.peekTokenIs(token.RPAREN) {
+				break
+			}
+			p.nextToken()
+			tuple.Elements = append(tuple.Elements, p.parseExpression(LOWEST))
+		}
+		exp = tuple
+	}
+
 	if !p.expectPeek(token.RPAREN) { // 3. 期待下一个Token是右括号
 		return nil // 4. 如果不是，返回nil
 	}
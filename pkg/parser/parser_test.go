@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"testing"
+
+	"punyGo/pkg/lexer"
+)
+
+// parseSource 解析一段程序，要求没有语法错误，返回语句条数
+func parseSource(t *testing.T, input string) int {
+	t.Helper()
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return len(program.Statements)
+}
+
+func TestTrailingSemicolonAfterBlockStatements(t *testing.T) {
+	tests := []string{
+		`enum V { A }; impl V { fn get(v) { 1 } }; 3`,
+		`let a = 1; for x in [1] { x }; 3`,
+		`let a = 1; try { 1 } catch (e) { 2 }; 3`,
+		`let a = 1; select { default { 1 } }; 3`,
+	}
+	for _, input := range tests {
+		if n := parseSource(t, input); n != 3 {
+			t.Errorf("%s: got %d statements, want 3", input, n)
+		}
+	}
+}
+
+func TestDuplicateParameters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`fn(a, a) { a }`, "1:7: duplicate parameter a"},
+		{`fn(a, b: int, a = 1) { a }`, "1:15: duplicate parameter a"},
+		{`fn(a, ...a) { a }`, "1:10: duplicate parameter a"},
+		{`fn f(x, y, x) { x }`, "1:12: duplicate parameter x"},
+		{`macro(a, a) { a }`, "1:10: duplicate parameter a"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if errs := p.Errors(); len(errs) == 0 || errs[0] != tt.want {
+			t.Errorf("%s: got errors %v, want %q", tt.input, errs, tt.want)
+		}
+	}
+	parseSource(t, `fn(a, b, ...c) { a }; fn(a) { fn(a) { a } }`)
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`match s { case Circle(r) { r } case Empty { 0 } default { 1 } }`, `match s { case Circle(r) { r } case Empty { 0 } default { 1 } }`},
+		{`let a = match f(x) { case Rect(_, h) { h } }; a`, `let a = match f(x) { case Rect(_, h) { h } };a`},
+		{`re.match("a")`, `re.match("a")`},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("parser errors for %q: %v", tt.input, p.Errors())
+		}
+		if got := program.String(); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+
+	errors := []struct {
+		input string
+		want  string
+	}{
+		{`match s { case Rect(a, a) { a } }`, "1:24: duplicate name a in pattern"},
+		{`match s { x }`, "1:11: expected case or default in match, got IDENT instead"},
+		{`match s { case 1 { x } }`, "1:16: expected next token to be IDENT, got INT instead"},
+		{`x.(`, "1:3: expected next token to be IDENT, got ( instead"},
+	}
+	for _, tt := range errors {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if errs := p.Errors(); len(errs) == 0 || errs[0] != tt.want {
+			t.Errorf("%s: got errors %v, want %q", tt.input, errs, tt.want)
+		}
+	}
+}
+
+func TestInterpolationErrorPositions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"a ${ 1 + }"`, "in string interpolation: 1:11: no prefix parse function for EOF found"},
+		{`"a\n${ 1 + }"`, "in string interpolation: 1:12: no prefix parse function for EOF found"},
+		{"let x = 1;\n  \"a ${ 1 + }\"", "in string interpolation: 2:13: no prefix parse function for EOF found"},
+		{`"${}"`, "in string interpolation: 1:4: empty expression"},
+		{`"a ${"`, "1:1: illegal token: unterminated string literal"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if errs := p.Errors(); len(errs) == 0 || errs[0] != tt.want {
+			t.Errorf("%s: got errors %v, want %q", tt.input, errs, tt.want)
+		}
+	}
+}
+
+func TestErrorColumnsCountCharacters(t *testing.T) {
+	p := New(lexer.New("let 名字 = ;"))
+	p.ParseProgram()
+	if errs := p.Errors(); len(errs) == 0 || errs[0] != "1:10: no prefix parse function for ; found" {
+		t.Errorf("got errors %v", errs)
+	}
+}
+
+func TestUnknownRegexFlag(t *testing.T) {
+	p := New(lexer.New(`re"a"q`))
+	p.ParseProgram()
+	if errs := p.Errors(); len(errs) == 0 || errs[0] != "1:1: unknown regex flag q, want one of i, m, s and U" {
+		t.Errorf("got errors %v", errs)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"punyGo/pkg/lexer"
 	"punyGo/pkg/object"
 	"punyGo/pkg/parser"
+	"punyGo/pkg/types"
 )
 
 const PROMPT = ">> "
@@ -18,11 +19,17 @@ const PROMPT = ">> "
 func Start(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
 	env := object.NewEnvironment(nil)
+	macroEnv := object.NewEnvironment(nil)
+	checker := types.NewChecker()
 
 	for {
 		fmt.Fprintf(out, PROMPT)
 		scanned := scanner.Scan()
 		if !scanned {
+			// report failures of tasks nobody waited for
+			for _, errObj := range evaluator.UnobservedTaskErrors() {
+				io.WriteString(out, "unobserved task error: "+errObj.Inspect()+"\n")
+			}
 			return
 		}
 
@@ -37,13 +44,31 @@ func Start(in io.Reader, out io.Writer) {
 			printParserErrors(out, par.Errors())
 			continue
 		}
+		if len(program.Statements) == 0 { // skip empty lines
+			continue
+		}
+
+		// define and expand macros
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, err := evaluator.ExpandMacros(program, macroEnv)
+		if err != nil {
+			io.WriteString(out, err.Inspect())
+			io.WriteString(out, "\n")
+			continue
+		}
+
+		// check types before evaluation
+		if errs := checker.Check(expanded); len(errs) != 0 {
+			printParserErrors(out, errs)
+			continue
+		}
 
 		// print AST
-		io.WriteString(out, program.String())
+		io.WriteString(out, expanded.String())
 		io.WriteString(out, "\n")
 
 		// evaluate AST
-		evaluated := evaluator.Eval(program, env)
+		evaluated := evaluator.Eval(expanded, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -5,6 +5,8 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // Token 起始字符所在的行，从 1 开始
+	Column  int // Token 起始字符所在的列，按字符计数，从 1 开始
 }
 
 const (
@@ -12,11 +14,16 @@ const (
 
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // 注释，只有要求保留注释的词法分析器才会产生
 
 	// 标识符 + 字面量
 
-	IDENT = "IDENT" // add, foobar, x, y, ...
-	INT   = "INT"   // 12345
+	IDENT  = "IDENT"  // add, foobar, x, y, ...
+	INT    = "INT"    // 12345
+	STRING = "STRING" // "foobar"
+	BYTES  = "BYTES"  // b"\x00\xff"
+	CHAR   = "CHAR"   // 'a'
+	REGEX  = "REGEX"  // re"[a-z]+"i
 
 	// 操作符
 
@@ -26,31 +33,105 @@ const (
 	BANG     = "!"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
+	QUESTION = "?"
+	COALESCE = "??"
 
-	LT     = "<"
-	GT     = ">"
-	EQ     = "=="
-	NOT_EQ = "!="
+	PIPE      = "|"
+	AMPERSAND = "&"
+	CARET     = "^"
+
+	LT          = "<"
+	GT          = ">"
+	EQ          = "=="
+	NOT_EQ      = "!="
+	REGEX_MATCH = "=~"
 
 	// 分隔符
 
 	COMMA     = ","
 	SEMICOLON = ";"
+	DOT       = "."
+	COLON     = ":"
+	DOTDOT    = ".."
+	DOTDOT_EQ = "..="
+	ELLIPSIS  = "..."
+	ARROW     = "->"
+
+	OPTIONAL_DOT      = "?."
+	OPTIONAL_LBRACKET = "?["
 
 	LPAREN = "("
 	RPAREN = ")"
 	LBRACE = "{"
 	RBRACE = "}"
 
+	SET_LBRACE = "#{"
+
+	LBRACKET = "["
+	RBRACKET = "]"
+
 	// 关键字
 
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	TRUE     = "TRUE"
+	FALSE    = "FALSE"
+	ENUM     = "ENUM"
+	THROW    = "THROW"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	RETURN   = "RETURN"
+	YIELD    = "YIELD"
+	SPAWN    = "SPAWN"
+	SELECT   = "SELECT"
+	CASE     = "CASE"
+	DEFAULT  = "DEFAULT"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	AS       = "AS"
+	MACRO    = "MACRO"
+	NIL      = "NIL"
+	FOR      = "FOR"
+	IN       = "IN"
+	IF       = "IF"
+	ELSE     = "ELSE"
+	DEFER    = "DEFER"
+	IMPL     = "IMPL"
+	IS       = "IS"
+	MATCH    = "MATCH"
 )
 
 var keywords = map[string]TokenType{
-	"fn":  FUNCTION,
-	"let": LET,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"enum":    ENUM,
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"return":  RETURN,
+	"yield":   YIELD,
+	"spawn":   SPAWN,
+	"select":  SELECT,
+	"case":    CASE,
+	"default": DEFAULT,
+	"import":  IMPORT,
+	"export":  EXPORT,
+	"as":      AS,
+	"macro":   MACRO,
+	"nil":     NIL,
+	"for":     FOR,
+	"in":      IN,
+	"if":      IF,
+	"else":    ELSE,
+	"defer":   DEFER,
+	"impl":    IMPL,
+	"is":      IS,
+	"match":   MATCH,
 }
 
 // LookupIdent 根据标识符返回对应的关键字标识
@@ -0,0 +1,416 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/token"
+)
+
+// Checker 保存类型检查的状态。同一个 Checker 可以依次检查多个程序，
+// 前面程序中的声明对后面的程序可见，REPL 依靠这一点逐行检查
+type Checker struct {
+	scope      *scope                  // 当前作用域
+	enums      map[string]*Named       // 已声明的枚举类型
+	enumParams map[string][]*TypeParam // 泛型枚举的类型参数
+	typeParams []map[string]*TypeParam // 类型参数的作用域，栈顶是最内层的泛型函数或枚举
+	errors     []string                // 本次检查发现的错误
+	returns    []Type                  // 正在检查的函数的返回值类型，栈顶是最内层的函数
+}
+
+// scope 记录一个作用域中变量的类型
+type scope struct {
+	vars  map[string]Type // 变量的类型
+	outer *scope          // 外层作用域
+}
+
+// lookup 由内向外查找变量的类型
+func (s *scope) lookup(name string) (Type, bool) {
+	for ; s != nil; s = s.outer {
+		if t, ok := s.vars[name]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// builtinTypes 记录内置函数的类型，没有列出的内置对象视为 any
+var builtinTypes = map[string]Type{
+	"len":    &Function{Params: []Type{Any}, Required: 1, Return: Int},
+	"str":    &Function{Params: []Type{Any}, Required: 1, Return: String},
+	"bytes":  &Function{Params: []Type{Any}, Required: 1, Return: Bytes},
+	"char":   &Function{Params: []Type{Any}, Required: 1, Return: Char},
+	"ord":    &Function{Params: []Type{Char}, Required: 1, Return: Int},
+	"divmod": &Function{Params: []Type{Int, Int}, Required: 2, Return: &Tuple{Elements: []Type{Int, Int}}},
+	"items":  &Function{Params: []Type{&Hash{Key: Any, Value: Any}}, Required: 1, Return: &Array{Element: &Tuple{Elements: []Type{Any, Any}}}},
+}
+
+// NewChecker 创建一个类型检查器，内置函数的类型已经声明在最外层的作用域中
+func NewChecker() *Checker {
+	c := &Checker{
+		scope:      &scope{vars: map[string]Type{}},
+		enums:      map[string]*Named{"Result": {Name: "Result"}},
+		enumParams: map[string][]*TypeParam{},
+	}
+	for name, t := range builtinTypes {
+		c.scope.vars[name] = t
+	}
+	c.scope.vars["Ok"] = &Function{Params: []Type{Any}, Names: []string{"value"}, Required: 1, Return: c.enums["Result"]}
+	c.scope.vars["Err"] = &Function{Params: []Type{Any}, Names: []string{"error"}, Required: 1, Return: c.enums["Result"]}
+	return c
+}
+
+// Check 使用新的检查器检查一个程序，返回发现的所有错误，没有错误时返回空列表
+func Check(program *ast.Program) []string {
+	return NewChecker().Check(program)
+}
+
+// Check 检查一个程序，返回本次发现的错误。错误信息与语法错误相同，以 "行:列: " 开头。
+// 有错误时撤销本次的所有声明，REPL 中被拒绝的一行不会影响后面的检查
+func (c *Checker) Check(program *ast.Program) []string {
+	c.errors = []string{}
+	defer c.rollback()()
+
+	ast.Modify(program, func(node ast.Node) ast.Node { // 1. 预先收集所有枚举名，类型标注可以引用在后面声明的枚举
+		if es, ok := node.(*ast.EnumStatement); ok {
+			if _, exists := c.enums[es.Name.Value]; !exists {
+				c.enums[es.Name.Value] = &Named{Name: es.Name.Value}
+				c.enumParams[es.Name.Value] = newTypeParams(es.TypeParams)
+			}
+		}
+		return node
+	})
+
+	for _, stmt := range program.Statements { // 2. 依次检查每条语句
+		c.statement(stmt)
+	}
+
+	return c.errors
+}
+
+// rollback 保存最外层作用域和枚举的声明，返回的函数在本次检查有错误时恢复它们
+func (c *Checker) rollback() func() {
+	vars := maps.Clone(c.scope.vars)
+	enums := maps.Clone(c.enums)
+	enumParams := maps.Clone(c.enumParams)
+	return func() {
+		if len(c.errors) != 0 {
+			c.scope.vars, c.enums, c.enumParams = vars, enums, enumParams
+		}
+	}
+}
+
+// errorf 记录一个错误，位置取自 tok
+func (c *Checker) errorf(tok token.Token, format string, args ...interface{}) {
+	c.errors = append(c.errors, positioned(tok, format, args...))
+}
+
+// positioned 格式化错误信息，并在前面加上 tok 所在的 "行:列: "
+func positioned(tok token.Token, format string, args ...interface{}) string {
+	msg := fmt.Sprintf(format, args...)
+	if tok.Line > 0 {
+		msg = fmt.Sprintf("%d:%d: %s", tok.Line, tok.Column, msg)
+	}
+	return msg
+}
+
+// declare 在当前作用域中声明变量的类型
+func (c *Checker) declare(name string, t Type) {
+	c.scope.vars[name] = t
+}
+
+// openScope 进入一个新的作用域，返回用于离开该作用域的函数
+func (c *Checker) openScope() func() {
+	outer := c.scope
+	c.scope = &scope{vars: map[string]Type{}, outer: outer}
+	return func() { c.scope = outer }
+}
+
+// newTypeParams 为声明的类型参数创建类型，没有类型参数时返回 nil
+func newTypeParams(ids []*ast.Identifier) []*TypeParam {
+	var params []*TypeParam
+	for _, id := range ids {
+		params = append(params, &TypeParam{Name: id.Value})
+	}
+	return params
+}
+
+// bindTypeParams 进入一个类型参数的作用域，返回用于离开该作用域的函数
+func (c *Checker) bindTypeParams(params []*TypeParam) func() {
+	scope := map[string]*TypeParam{}
+	for _, p := range params {
+		scope[p.Name] = p
+	}
+	c.typeParams = append(c.typeParams, scope)
+	return func() { c.typeParams = c.typeParams[:len(c.typeParams)-1] }
+}
+
+// lookupTypeParam 由内向外查找类型参数
+func (c *Checker) lookupTypeParam(name string) (*TypeParam, bool) {
+	for i := len(c.typeParams) - 1; i >= 0; i-- {
+		if p, ok := c.typeParams[i][name]; ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// resolve 把类型标注转换为类型，未知的类型名报告错误并视为 any
+func (c *Checker) resolve(te ast.TypeExpression) Type {
+	switch te := te.(type) {
+	case nil:
+		return Any
+	case *ast.NamedType:
+		if p, ok := c.lookupTypeParam(te.Name); ok && len(te.Arguments) == 0 { // 1. 类型参数优先于同名的类型
+			return p
+		}
+		if t, ok := c.enums[te.Name]; ok {
+			if len(te.Arguments) == 0 {
+				return t
+			}
+			if want := len(c.enumParams[te.Name]); want != len(te.Arguments) { // 2. 泛型枚举的类型实参个数必须与类型参数相同
+				c.errorf(te.Token, "type %s expects %d type arguments, got %d", te.Name, want, len(te.Arguments))
+				return t
+			}
+			named := &Named{Name: te.Name}
+			for _, a := range te.Arguments {
+				named.Args = append(named.Args, c.resolve(a))
+			}
+			return named
+		}
+		if len(te.Arguments) > 0 {
+			c.errorf(te.Token, "type %s does not take type arguments", te.Name)
+			return Any
+		}
+		if t, ok := basicTypes[te.Name]; ok {
+			return t
+		}
+		c.errorf(te.Token, "unknown type %s", te.Name)
+		return Any
+	case *ast.ArrayType:
+		return &Array{Element: c.resolve(te.Element)}
+	case *ast.HashType:
+		return &Hash{Key: c.resolve(te.Key), Value: c.resolve(te.Value)}
+	case *ast.SetType:
+		return &Set{Element: c.resolve(te.Element)}
+	case *ast.TupleType:
+		elements := make([]Type, len(te.Elements))
+		for i, e := range te.Elements {
+			elements[i] = c.resolve(e)
+		}
+		return &Tuple{Elements: elements}
+	case *ast.FunctionType:
+		params := make([]Type, len(te.Parameters))
+		for i, p := range te.Parameters {
+			params[i] = c.resolve(p)
+		}
+		return &Function{Params: params, Required: len(params), Return: c.resolve(te.Return)}
+	}
+	return Any
+}
+
+// statement 检查一条语句
+func (c *Checker) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		c.expression(stmt.Expression)
+
+	case *ast.LetStatement:
+		c.letStatement(stmt)
+
+	case *ast.FunctionStatement:
+		fl := stmt.Function
+		fn := c.signature(fl, nil)
+		c.declare(fl.Name, fn) // 1. 先声明函数，函数体中可以递归调用
+		c.functionBody(fl, fn)
+
+	case *ast.ReturnStatement:
+		if n := len(c.returns); n > 0 { // 1. 返回值必须符合函数的返回值类型
+			c.expect(stmt.ReturnValue, c.returns[n-1], stmt.Token, "return")
+		} else {
+			c.expression(stmt.ReturnValue)
+		}
+
+	case *ast.EnumStatement:
+		named := c.enums[stmt.Name.Value]
+		params := c.enumParams[stmt.Name.Value]
+		result := named
+		if len(params) > 0 { // 1. 泛型枚举的构造函数是泛型函数，返回以类型参数为实参的枚举类型
+			result = &Named{Name: named.Name}
+			for _, p := range params {
+				result.Args = append(result.Args, p)
+			}
+		}
+		closeParams := c.bindTypeParams(params)
+		for _, v := range stmt.Variants { // 2. 有字段的变体是构造函数，没有字段的变体是枚举值
+			if len(v.Fields) == 0 {
+				c.declare(v.Name.Value, named)
+				continue
+			}
+			fn := &Function{TypeParams: params, Required: len(v.Fields), Return: result}
+			for i, f := range v.Fields {
+				var te ast.TypeExpression
+				if i < len(v.FieldTypes) {
+					te = v.FieldTypes[i]
+				}
+				fn.Params = append(fn.Params, c.resolve(te))
+				fn.Names = append(fn.Names, f.Value)
+			}
+			c.declare(v.Name.Value, fn)
+		}
+		closeParams()
+		c.declare(stmt.Name.Value, Any)
+
+	case *ast.ImplStatement:
+		receiver, ok := c.enums[stmt.Name.Value]
+		if !ok {
+			c.errorf(stmt.Name.Token, "unknown type %s", stmt.Name.Value)
+		}
+		for _, m := range stmt.Methods { // 1. 没有标注的接收者参数视为该枚举类型
+			if ok {
+				c.function(m, receiver)
+			} else {
+				c.function(m, nil)
+			}
+		}
+
+	case *ast.BlockStatement:
+		defer c.openScope()()
+		c.block(stmt)
+
+	case *ast.ForStatement:
+		elem := c.elementType(c.expression(stmt.Iterable), stmt.Token)
+		defer c.openScope()()
+		c.declare(stmt.Variable.Value, elem)
+		c.block(stmt.Body)
+
+	case *ast.TryStatement:
+		reported := len(c.errors)
+		c.scopedBlock(stmt.Block)
+		if stmt.Catch != nil {
+			c.errors = c.errors[:reported] // 1. 有 catch 时 try 语句块中的类型错误在运行时会被捕获，不报告
+			closeScope := c.openScope()
+			if stmt.CatchParam != nil {
+				c.declare(stmt.CatchParam.Value, Any)
+			}
+			c.block(stmt.Catch)
+			closeScope()
+		}
+		c.scopedBlock(stmt.Finally)
+
+	case *ast.ThrowStatement:
+		c.expression(stmt.Value)
+
+	case *ast.YieldStatement:
+		c.expression(stmt.Value)
+
+	case *ast.DeferStatement:
+		c.expression(stmt.Call)
+
+	case *ast.SelectStatement:
+		for _, sc := range stmt.Cases {
+			c.expression(sc.Comm)
+			closeScope := c.openScope()
+			if sc.Name != nil {
+				c.declare(sc.Name.Value, Any)
+			}
+			c.block(sc.Body)
+			closeScope()
+		}
+		c.scopedBlock(stmt.Default)
+
+	case *ast.ImportStatement:
+		c.declare(stmt.Alias.Value, Any)
+
+	case *ast.ExportStatement:
+		c.statement(stmt.Statement)
+	}
+}
+
+// letStatement 检查 let 语句，有类型标注时变量的类型是标注的类型，否则是值的类型
+func (c *Checker) letStatement(stmt *ast.LetStatement) {
+	fl, isFunction := stmt.Value.(*ast.FunctionLiteral)
+	isFunction = isFunction && stmt.Name != nil
+
+	var t Type
+	switch {
+	case stmt.Type != nil: // 1. 值必须符合类型标注，变量的类型是标注的类型
+		t = c.resolve(stmt.Type)
+		if isFunction { // 函数在求值前已经绑定到变量名，函数体中可以递归调用
+			c.declare(stmt.Name.Value, t)
+		}
+		c.expect(stmt.Value, t, stmt.Token, "let "+letNames(stmt))
+	case isFunction: // 2. 没有标注时先按函数签名声明，函数体中的递归调用不会看到外层同名变量
+		fn := c.signature(fl, nil)
+		c.declare(stmt.Name.Value, fn)
+		t = c.functionBody(fl, fn)
+	default:
+		t = c.expression(stmt.Value)
+	}
+
+	if stmt.Name != nil {
+		c.declare(stmt.Name.Value, t)
+		return
+	}
+
+	for i, name := range stmt.Names { // 3. 解构赋值按元素声明类型
+		elem := Type(Any)
+		switch t := t.(type) {
+		case *Tuple:
+			if len(t.Elements) != len(stmt.Names) {
+				c.errorf(stmt.Token, "cannot unpack %d values into %d names", len(t.Elements), len(stmt.Names))
+				return
+			}
+			elem = t.Elements[i]
+		case *Array:
+			elem = t.Element
+		default:
+			if t != Any {
+				c.errorf(stmt.Token, "cannot unpack %s", t)
+				return
+			}
+		}
+		c.declare(name.Value, elem)
+	}
+}
+
+// letNames 返回 let 语句声明的变量名，用于错误信息
+func letNames(stmt *ast.LetStatement) string {
+	if stmt.Name != nil {
+		return stmt.Name.Value
+	}
+	names := ""
+	for i, n := range stmt.Names {
+		if i > 0 {
+			names += ", "
+		}
+		names += n.Value
+	}
+	return "(" + names + ")"
+}
+
+// block 在当前作用域中检查语句块，返回语句块的值的类型：最后一条语句是表达式时为其类型，否则为 any
+func (c *Checker) block(block *ast.BlockStatement) Type {
+	if len(block.Statements) == 0 {
+		return Nil
+	}
+	last := len(block.Statements) - 1
+	for _, stmt := range block.Statements[:last] {
+		c.statement(stmt)
+	}
+	if es, ok := block.Statements[last].(*ast.ExpressionStatement); ok {
+		return c.expression(es.Expression)
+	}
+	c.statement(block.Statements[last])
+	return Any
+}
+
+// scopedBlock 在新的作用域中检查语句块，语句块可以为 nil
+func (c *Checker) scopedBlock(block *ast.BlockStatement) Type {
+	if block == nil {
+		return Nil
+	}
+	defer c.openScope()()
+	return c.block(block)
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/parser"
+)
+
+// checkSource 解析并检查一段程序，返回发现的错误
+func checkSource(t *testing.T, input string) []string {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return Check(program)
+}
+
+// expectErrors 检查程序，要求发现的错误与 want 中的子串一一对应
+func expectErrors(t *testing.T, input string, want ...string) {
+	t.Helper()
+	errs := checkSource(t, input)
+	if len(errs) != len(want) {
+		t.Fatalf("%s: got errors %v, want %d", input, errs, len(want))
+	}
+	for i := range want {
+		if !strings.Contains(errs[i], want[i]) {
+			t.Errorf("%s: error %q does not contain %q", input, errs[i], want[i])
+		}
+	}
+}
+
+func TestAnnotatedLiteralElements(t *testing.T) {
+	expectErrors(t, `let arr: [int] = [1, "a"];`, "cannot use string as int in let arr")
+	expectErrors(t, `let arr: [int] = [1, 2, ...[3]];`)
+	expectErrors(t, `let h: {string: int} = {"a": 1, "b": "x"};`, "cannot use string as int in let h")
+	expectErrors(t, `let xs: [[int]] = [[1], ["a"]];`, "cannot use string as int in let xs")
+	expectErrors(t, `let f = fn() -> [int] { return [1, true]; };`, "cannot use bool as int in return")
+	expectErrors(t, `let xs: [any] = [1, "a"];`)
+}
+
+func TestTryBodyErrorsAreCatchable(t *testing.T) {
+	expectErrors(t, `try { 1 + "a" } catch (e) { e.kind }`)
+	expectErrors(t, `try { 1 + "a" } finally { 2 }`, "type mismatch: int + string")
+}
+
+func TestTypeParamsAreOpaque(t *testing.T) {
+	expectErrors(t, `fn bad<T>(a: T) -> T { a + 1 }`, "unknown operator: T + int")
+	expectErrors(t, `fn neg<T>(a: T) -> T { -a }`, "unknown operator: -T")
+	expectErrors(t, `fn ne<T, U>(a: T, b: U) -> bool { a != b }`, "unknown operator: T != U")
+	expectErrors(t, `fn eq<T>(a: T, b: T) -> bool { a == b }`)
+	expectErrors(t, `fn isNil<T>(a: T) -> bool { a == nil }`)
+}
+
+func TestRecursiveLetSeesItself(t *testing.T) {
+	expectErrors(t, `let f = 1; let f = fn(n: int) -> int { if (n == 0) { 0 } else { f(n - 1) } };`)
+	expectErrors(t, `let f = fn(n: int) -> int { f("a") };`, "cannot use string as int")
+	expectErrors(t, `let f: fn(int) -> int = fn(n) { f(n) };`)
+}
+
+func TestUnannotatedFunctionBodiesAreUnchecked(t *testing.T) {
+	expectErrors(t, `let g = fn() { 1 + true }; 5`)
+	expectErrors(t, `fn g(a) { a + 1; 1 + true }`)
+	expectErrors(t, `let g = fn(a: int) { 1 + true };`, "type mismatch: int + bool")
+	expectErrors(t, `fn g() -> int { 1 + true }`, "type mismatch: int + bool")
+}
+
+func TestRejectedProgramIsRolledBack(t *testing.T) {
+	c := NewChecker()
+	check := func(input string) []string {
+		p := parser.New(lexer.New(input))
+		return c.Check(p.ParseProgram())
+	}
+	if errs := check(`let x = "s"; let n: int = true;`); len(errs) != 1 {
+		t.Fatalf("got errors %v, want 1", errs)
+	}
+	if errs := check(`let y: int = x;`); len(errs) != 0 {
+		t.Errorf("declaration from a rejected program is still visible: %v", errs)
+	}
+	if errs := check(`let z = "s";`); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if errs := check(`let w: int = z;`); len(errs) != 1 {
+		t.Errorf("declaration from an accepted program is lost: %v", errs)
+	}
+}
+
+func TestTasksAndGeneratorsAreOpaque(t *testing.T) {
+	expectErrors(t, `let f = fn() { 1 }; let n: int = spawn f();`, "cannot use task as int in let n")
+	expectErrors(t, `let f = fn() { 1 }; for x in spawn f() { x }`, "task is not iterable")
+	expectErrors(t, `let g = fn*() { yield 1; }; let it: generator = g(); for x in it { x }`)
+	expectErrors(t, `let g = fn*() { yield 1; }; let n: int = g();`, "cannot use generator as int in let n")
+}
+
+func TestHashMembers(t *testing.T) {
+	expectErrors(t, `let h: {string: int} = {"a": 1}; let n: int = h.a; let m: int = h?.b;`)
+	expectErrors(t, `let h: {string: int} = {"a": 1}; let s: string = h.a;`, "cannot use int as string in let s")
+	expectErrors(t, `let h: {int: int} = {1: 2}; let s: string = h.a;`)
+}
+
+func TestMatchExpression(t *testing.T) {
+	enum := "enum Shape { Circle(r: int), Rect(w: int, h: int), Empty }; enum Color { Red }; "
+	expectErrors(t, enum+`let area = fn(s: Shape) -> int { match s { case Circle(r) { r * r } case Rect(_, h) { h } case Empty { 0 } } };`)
+	expectErrors(t, enum+`let s: string = match Circle(1) { case Circle(r) { r } };`, "cannot use int as string in let s")
+	expectErrors(t, enum+`match Red { case Circle(r) { r } default { 0 } };`, "case Circle can never match Color")
+	expectErrors(t, enum+`match Empty { case Rect(w) { w } case Empty(x) { x } };`,
+		"wrong number of names in pattern Rect: want=2, got=1",
+		"wrong number of names in pattern Empty: want=0, got=1")
+	expectErrors(t, enum+`let f = fn(x) { x }; match Empty { case f { 1 } };`, "case f in match must be a variant, got fn(any) -> any")
+	expectErrors(t, `enum Option<T> { Some(value: T), None }; match Some(1) { case Some(v) { v } case None { 0 } };`)
+}
@@ -0,0 +1,871 @@
+package types
+
+import (
+	"fmt"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/token"
+)
+
+// expression 检查表达式并返回其类型，无法确定的类型为 any
+func (c *Checker) expression(exp ast.Expression) Type {
+	switch exp := exp.(type) {
+	case nil:
+		return Nil
+
+	// 字面量
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.Boolean:
+		return Bool
+	case *ast.StringLiteral:
+		return String
+	case *ast.InterpolatedString:
+		for _, part := range exp.Parts {
+			c.expression(part)
+		}
+		return String
+	case *ast.BytesLiteral:
+		return Bytes
+	case *ast.CharLiteral:
+		return Char
+	case *ast.RegexLiteral:
+		return Regex
+	case *ast.NilLiteral:
+		return Nil
+
+	case *ast.Identifier:
+		if t, ok := c.scope.lookup(exp.Value); ok {
+			return t
+		}
+		return Any // 未声明的标识符可能来自模块或运行时环境，交给求值器处理
+
+	case *ast.PrefixExpression:
+		return c.prefixExpression(exp)
+	case *ast.InfixExpression:
+		return c.infixExpression(exp)
+	case *ast.PostfixExpression:
+		c.expression(exp.Left)
+		return Any
+
+	case *ast.IfExpression:
+		c.expression(exp.Condition)
+		consequence := c.scopedBlock(exp.Consequence)
+		alternative := c.scopedBlock(exp.Alternative)
+		return join(consequence, alternative)
+	case *ast.MatchExpression:
+		return c.matchExpression(exp)
+
+	case *ast.FunctionLiteral:
+		return c.function(exp, nil)
+	case *ast.MacroLiteral:
+		return Any // 宏在检查之前已经展开，宏体中的 quote 不是普通代码
+
+	case *ast.CallExpression:
+		return c.callExpression(exp)
+	case *ast.SpawnExpression:
+		c.expression(exp.Call)
+		return Task
+
+	case *ast.ArrayLiteral:
+		return &Array{Element: c.elementsType(exp.Elements)}
+	case *ast.SetLiteral:
+		return &Set{Element: c.elementsType(exp.Elements)}
+	case *ast.TupleLiteral:
+		elements := []Type{}
+		spread := false
+		for _, e := range exp.Elements {
+			if s, ok := e.(*ast.SpreadExpression); ok {
+				c.expression(s.Value)
+				spread = true
+				continue
+			}
+			elements = append(elements, c.expression(e))
+		}
+		if spread { // 1. 有展开时元素个数只有运行时才知道
+			return Any
+		}
+		return &Tuple{Elements: elements}
+	case *ast.HashLiteral:
+		return c.hashLiteral(exp)
+	case *ast.RangeExpression:
+		for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+			if bound == nil {
+				continue
+			}
+			if t := c.expression(bound); !assignable(t, Int) {
+				c.errorf(exp.Token, "range bounds must be int, got %s", t)
+			}
+		}
+		return Range
+
+	case *ast.ListComprehension:
+		defer c.openScope()()
+		c.comprehensionClause(exp.Clause)
+		return &Array{Element: c.expression(exp.Element)}
+	case *ast.HashComprehension:
+		defer c.openScope()()
+		c.comprehensionClause(exp.Clause)
+		return &Hash{Key: c.expression(exp.Key), Value: c.expression(exp.Value)}
+
+	case *ast.MemberExpression:
+		if h, ok := c.expression(exp.Object).(*Hash); ok && h.Key == String { // 字符串键的哈希按键取值
+			return h.Value
+		}
+		return Any
+	case *ast.IndexExpression:
+		return c.indexExpression(exp)
+	case *ast.SliceExpression:
+		return c.sliceExpression(exp)
+
+	case *ast.SpreadExpression:
+		c.expression(exp.Value)
+		return Any
+	case *ast.KeywordArgument:
+		c.expression(exp.Value)
+		return Any
+	}
+	return Any
+}
+
+// signature 根据函数字面量的类型标注构造函数类型，receiver 不为 nil 时作为没有标注的第一个形参的类型
+func (c *Checker) signature(fl *ast.FunctionLiteral, receiver Type) *Function {
+	params := newTypeParams(fl.TypeParams) // 1. 泛型函数的类型参数只在签名和函数体中可见
+	defer c.bindTypeParams(params)()
+
+	fn := &Function{TypeParams: params, Return: c.resolve(fl.ReturnType)}
+	for i, p := range fl.Parameters {
+		var te ast.TypeExpression
+		if i < len(fl.ParamTypes) {
+			te = fl.ParamTypes[i]
+		}
+		t := c.resolve(te)
+		if i == 0 && te == nil && receiver != nil {
+			t = receiver
+		}
+		fn.Params = append(fn.Params, t)
+		fn.Names = append(fn.Names, p.Value)
+		if i >= len(fl.Defaults) || fl.Defaults[i] == nil {
+			fn.Required++
+		}
+	}
+
+	if fl.Rest != nil { // 2. 可变参数总是数组
+		fn.Rest = &Array{Element: Any}
+		if fl.RestType != nil {
+			fn.Rest = c.resolve(fl.RestType)
+			if _, ok := fn.Rest.(*Array); !ok && fn.Rest != Any {
+				c.errorf(fl.Rest.Token, "variadic parameter %s must have an array type, got %s", fl.Rest.Value, fn.Rest)
+				fn.Rest = &Array{Element: Any}
+			}
+		}
+	}
+
+	if fl.IsGenerator { // 3. 调用生成器函数得到的是生成器，不是返回值
+		fn.Return = Generator
+	}
+	return fn
+}
+
+// function 检查函数字面量的默认值和函数体，返回函数的类型
+func (c *Checker) function(fl *ast.FunctionLiteral, receiver Type) Type {
+	return c.functionBody(fl, c.signature(fl, receiver))
+}
+
+// functionBody 按签名 fn 检查函数字面量的默认值和函数体，返回函数的类型。
+// 没有任何类型标注的函数不报告函数体中的错误，这些错误只在调用时由求值器发现
+func (c *Checker) functionBody(fl *ast.FunctionLiteral, fn *Function) Type {
+	if !annotated(fl) {
+		n := len(c.errors)
+		defer func() { c.errors = c.errors[:n] }()
+	}
+	defer c.openScope()()
+	defer c.bindTypeParams(fn.TypeParams)()
+
+	for i, p := range fl.Parameters { // 1. 默认值在函数调用环境中求值，可以引用之前的形参
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			c.expect(fl.Defaults[i], fn.Params[i], p.Token, "default of parameter "+p.Value)
+		}
+		c.declare(p.Value, fn.Params[i])
+	}
+	if fl.Rest != nil {
+		c.declare(fl.Rest.Value, fn.Rest)
+	}
+
+	expected := c.resolve(fl.ReturnType) // 2. 生成器中的 return 只是结束迭代，仍按标注检查
+	c.returns = append(c.returns, expected)
+	body := c.block(fl.Body)
+	c.returns = c.returns[:len(c.returns)-1]
+
+	if fl.ReturnType != nil && !fl.IsGenerator && !endsWithReturn(fl.Body) && !assignable(body, expected) { // 3. 检查隐式返回的最后一个表达式
+		c.errorf(fl.Token, "cannot use %s as %s in return of %s", body, expected, functionName(fl))
+	}
+	return fn
+}
+
+// annotated 判断函数字面量是否有形参、返回值或类型参数的标注
+func annotated(fl *ast.FunctionLiteral) bool {
+	if fl.ReturnType != nil || fl.RestType != nil || len(fl.TypeParams) != 0 {
+		return true
+	}
+	for _, te := range fl.ParamTypes {
+		if te != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithReturn 判断语句块是否以 return 语句结束
+func endsWithReturn(block *ast.BlockStatement) bool {
+	if n := len(block.Statements); n > 0 {
+		_, ok := block.Statements[n-1].(*ast.ReturnStatement)
+		return ok
+	}
+	return false
+}
+
+// functionName 返回函数在错误信息中的名字
+func functionName(fl *ast.FunctionLiteral) string {
+	if fl.Name != "" {
+		return fl.Name
+	}
+	return "anonymous function"
+}
+
+// prefixExpression 检查前缀表达式
+func (c *Checker) prefixExpression(exp *ast.PrefixExpression) Type {
+	right := c.expression(exp.Right)
+	switch exp.Operator { // 类型参数是不透明的类型，不支持前缀操作符
+	case "!":
+		if right != Any && right != Bool {
+			c.errorf(exp.Token, "unknown operator: !%s", right)
+		}
+		return Bool
+	case "-":
+		if right != Any && right != Int {
+			c.errorf(exp.Token, "unknown operator: -%s", right)
+			return Any
+		}
+		return right
+	}
+	return Any
+}
+
+// infixExpression 检查中缀表达式，规则与求值器相同：同类型的操作数按类型支持的操作符运算，
+// 不同类型之间只能与 nil 比较相等
+func (c *Checker) infixExpression(exp *ast.InfixExpression) Type {
+	left := c.expression(exp.Left)
+	right := c.expression(exp.Right)
+	op := exp.Operator
+
+	switch op { // 1. 与操作数类型无关的操作符
+	case "??":
+		if left == Nil {
+			return right
+		}
+		return join(left, right)
+	case "in", "is":
+		return Bool
+	case "=~":
+		if right != Any && right != Regex {
+			c.errorf(exp.Token, "right operand of =~ must be regex, got %s", right)
+		}
+		return Bool
+	}
+
+	if _, ok := left.(*Named); ok { // 2. 枚举值可以通过特殊方法重载操作符
+		return Any
+	}
+	if _, ok := right.(*Named); ok {
+		return Any
+	}
+
+	_, leftParam := left.(*TypeParam)
+	_, rightParam := right.(*TypeParam)
+	if leftParam || rightParam { // 3. 类型参数是不透明的类型，只能与同一个类型参数、nil 或 any 比较相等
+		equality := op == "==" || op == "!="
+		if equality && (left == right || left == Nil || right == Nil || left == Any || right == Any) {
+			return Bool
+		}
+		c.errorf(exp.Token, "unknown operator: %s %s %s", left, op, right)
+		return Any
+	}
+
+	if left == Any || right == Any { // 4. 有一侧未知时只能确定比较的结果
+		if op == "==" || op == "!=" || op == "<" || op == ">" {
+			return Bool
+		}
+		return Any
+	}
+
+	if kind(left) != kind(right) { // 5. 不同类型之间只能与 nil 比较相等
+		if (op == "==" || op == "!=") && (left == Nil || right == Nil) {
+			return Bool
+		}
+		c.errorf(exp.Token, "type mismatch: %s %s %s", left, op, right)
+		return Any
+	}
+
+	if op == "==" || op == "!=" { // 6. 同类型的值总可以比较相等
+		return Bool
+	}
+
+	switch { // 7. 同类型的值按类型支持的操作符运算
+	case left == Int:
+		switch op {
+		case "+", "-", "*", "/", "%", "|", "&", "^":
+			return Int
+		case "<", ">":
+			return Bool
+		}
+	case left == String && op == "+":
+		return String
+	case left == Bytes && op == "+":
+		return Bytes
+	case left == Char && (op == "<" || op == ">"):
+		return Bool
+	case kind(left) == "set" && (op == "|" || op == "&" || op == "-" || op == "^"):
+		return join(left, right)
+	}
+	c.errorf(exp.Token, "unknown operator: %s %s %s", left, op, right)
+	return Any
+}
+
+// callExpression 检查函数调用的实参个数和类型，返回调用结果的类型
+func (c *Checker) callExpression(exp *ast.CallExpression) Type {
+	if ident, ok := exp.Function.(*ast.Identifier); ok { // 1. quote 的实参是未求值的 AST，不做检查
+		if _, declared := c.scope.lookup(ident.Value); !declared && (ident.Value == "quote" || ident.Value == "unquote") {
+			return Any
+		}
+	}
+
+	callee := c.expression(exp.Function)
+	positional := []Type{}
+	keywords := []*ast.KeywordArgument{}
+	keywordTypes := []Type{}
+	spread := false
+	for _, arg := range exp.Arguments { // 2. 按顺序检查实参，展开之后的位置实参无法确定对应的形参
+		switch arg := arg.(type) {
+		case *ast.KeywordArgument:
+			keywords = append(keywords, arg)
+			keywordTypes = append(keywordTypes, c.expression(arg.Value))
+		case *ast.SpreadExpression:
+			c.expression(arg.Value)
+			spread = true
+		default:
+			t := c.expression(arg)
+			if !spread {
+				positional = append(positional, t)
+			}
+		}
+	}
+
+	fn, ok := callee.(*Function)
+	if !ok {
+		if callee != Any {
+			c.errorf(exp.Token, "not a function: %s", callee)
+		}
+		return Any
+	}
+
+	name := calleeName(exp.Function)
+	got := len(positional) + len(keywords)
+	if !spread && (got < fn.Required || (fn.Rest == nil && got > len(fn.Params))) { // 3. 检查实参个数
+		c.errorf(exp.Token, "wrong number of arguments for %s: want=%s, got=%d", name, arity(fn), got)
+		return eraseTypeParams(fn).Return
+	}
+
+	if len(fn.TypeParams) > 0 { // 4. 泛型函数根据实参确定类型参数，类型参数的实例互相矛盾时不再逐个检查实参
+		var ok bool
+		if fn, ok = c.instantiate(exp, fn, name, positional, keywords, keywordTypes); !ok {
+			return fn.Return
+		}
+	}
+
+	for i, t := range positional { // 5. 检查位置实参的类型
+		param, label := parameterFor(fn, i)
+		if param != nil && !assignable(t, param) {
+			c.errorf(exp.Token, "cannot use %s as %s for %s of %s", t, param, label, name)
+		}
+	}
+
+	for i, kw := range keywords { // 6. 检查关键字实参的名字和类型
+		idx := parameterIndex(fn, kw.Name.Value)
+		if idx < 0 {
+			if fn.Names != nil {
+				c.errorf(kw.Token, "%s got an unexpected keyword argument %s", name, kw.Name.Value)
+			}
+			continue
+		}
+		if !assignable(keywordTypes[i], fn.Params[idx]) {
+			c.errorf(kw.Token, "cannot use %s as %s for parameter %s of %s", keywordTypes[i], fn.Params[idx], kw.Name.Value, name)
+		}
+	}
+
+	return fn.Return
+}
+
+// instantiate 对照实参与形参的类型确定泛型函数每个类型参数的实例，返回替换类型参数后的函数类型；
+// 没有确定的类型参数替换为 any。同一个类型参数得到互相矛盾的实例时报告错误并返回 false
+func (c *Checker) instantiate(exp *ast.CallExpression, fn *Function, name string, positional []Type, keywords []*ast.KeywordArgument, keywordTypes []Type) (*Function, bool) {
+	bindings := map[*TypeParam]Type{}
+	for _, p := range fn.TypeParams {
+		bindings[p] = nil
+	}
+
+	var conflict *typeConflict
+	for i, t := range positional { // 1. 依次对照位置实参和关键字实参
+		if param, _ := parameterFor(fn, i); param != nil && conflict == nil {
+			conflict = matchTypeParams(param, t, bindings)
+		}
+	}
+	for i, kw := range keywords {
+		if idx := parameterIndex(fn, kw.Name.Value); idx >= 0 && conflict == nil {
+			conflict = matchTypeParams(fn.Params[idx], keywordTypes[i], bindings)
+		}
+	}
+
+	inst := replaceTypeParams(fn, func(p *TypeParam) Type { // 2. 替换类型参数
+		if t, ok := bindings[p]; ok {
+			if t == nil {
+				return Any
+			}
+			return t
+		}
+		return p
+	}).(*Function)
+
+	if conflict != nil {
+		c.errorf(exp.Token, "conflicting instantiations of %s in call to %s: %s and %s", conflict.param.Name, name, conflict.first, conflict.second)
+		return inst, false
+	}
+	return inst, true
+}
+
+// typeConflict 记录一个类型参数得到的两个互相矛盾的实例
+type typeConflict struct {
+	param         *TypeParam // 类型参数
+	first, second Type       // 先后得到的实例
+}
+
+// matchTypeParams 对照形参类型 param 与实参类型 arg，把 param 中类型参数的实例记录在 bindings 中。
+// 只处理 bindings 中已有的类型参数，实例为 any 时不记录
+func matchTypeParams(param, arg Type, bindings map[*TypeParam]Type) *typeConflict {
+	if arg == Any {
+		return nil
+	}
+
+	switch p := param.(type) {
+	case *TypeParam:
+		prev, ok := bindings[p]
+		switch {
+		case !ok:
+		case prev == nil:
+			bindings[p] = arg
+		case !assignable(arg, prev) && !assignable(prev, arg):
+			return &typeConflict{param: p, first: prev, second: arg}
+		}
+	case *Array:
+		if a, ok := arg.(*Array); ok {
+			return matchTypeParams(p.Element, a.Element, bindings)
+		}
+	case *Set:
+		if a, ok := arg.(*Set); ok {
+			return matchTypeParams(p.Element, a.Element, bindings)
+		}
+	case *Hash:
+		if a, ok := arg.(*Hash); ok {
+			if conflict := matchTypeParams(p.Key, a.Key, bindings); conflict != nil {
+				return conflict
+			}
+			return matchTypeParams(p.Value, a.Value, bindings)
+		}
+	case *Tuple:
+		if a, ok := arg.(*Tuple); ok && len(a.Elements) == len(p.Elements) {
+			for i := range p.Elements {
+				if conflict := matchTypeParams(p.Elements[i], a.Elements[i], bindings); conflict != nil {
+					return conflict
+				}
+			}
+		}
+	case *Function:
+		if a, ok := arg.(*Function); ok && len(a.Params) == len(p.Params) {
+			for i := range p.Params {
+				if conflict := matchTypeParams(p.Params[i], a.Params[i], bindings); conflict != nil {
+					return conflict
+				}
+			}
+			return matchTypeParams(p.Return, a.Return, bindings)
+		}
+	case *Named:
+		if a, ok := arg.(*Named); ok && a.Name == p.Name && len(a.Args) == len(p.Args) {
+			for i := range p.Args {
+				if conflict := matchTypeParams(p.Args[i], a.Args[i], bindings); conflict != nil {
+					return conflict
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parameterFor 返回第 i 个位置实参对应的形参类型及其在错误信息中的名字，没有对应的形参时类型为 nil
+func parameterFor(fn *Function, i int) (Type, string) {
+	switch {
+	case i < len(fn.Params):
+		return fn.Params[i], parameterLabel(fn, i)
+	case fn.Rest != nil:
+		if rest, ok := fn.Rest.(*Array); ok {
+			return rest.Element, "variadic parameter"
+		}
+	}
+	return nil, ""
+}
+
+// parameterIndex 返回名为 name 的形参的位置，没有该形参时返回 -1
+func parameterIndex(fn *Function, name string) int {
+	for i, n := range fn.Names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// calleeName 返回被调用的函数在错误信息中的名字
+func calleeName(exp ast.Expression) string {
+	if ident, ok := exp.(*ast.Identifier); ok {
+		return ident.Value
+	}
+	return "function"
+}
+
+// arity 返回函数接受的实参个数的描述，格式与求值器的错误信息相同
+func arity(fn *Function) string {
+	switch {
+	case fn.Rest != nil:
+		return fmt.Sprintf(">=%d", fn.Required)
+	case fn.Required != len(fn.Params):
+		return fmt.Sprintf("%d..%d", fn.Required, len(fn.Params))
+	}
+	return fmt.Sprintf("%d", fn.Required)
+}
+
+// parameterLabel 返回第 i 个形参在错误信息中的名字
+func parameterLabel(fn *Function, i int) string {
+	if i < len(fn.Names) {
+		return "parameter " + fn.Names[i]
+	}
+	return fmt.Sprintf("argument %d", i+1)
+}
+
+// elementsType 检查数组或集合字面量的元素，返回元素的公共类型
+func (c *Checker) elementsType(elements []ast.Expression) Type {
+	var elem Type
+	for _, e := range elements {
+		t := Type(nil)
+		if s, ok := e.(*ast.SpreadExpression); ok { // 1. 展开的元素取被展开对象的元素类型
+			t = c.elementType(c.expression(s.Value), s.Token)
+		} else {
+			t = c.expression(e)
+		}
+		if elem == nil {
+			elem = t
+		} else {
+			elem = join(elem, t)
+		}
+	}
+	if elem == nil { // 2. 空字面量的元素类型未知
+		return Any
+	}
+	return elem
+}
+
+// expect 检查表达式的值能否用作 want 类型，不能时在 tok 处报告错误，context 说明值的用途。
+// 数组、集合、元组和哈希字面量逐个检查元素，因此 [1, "a"] 不会因为元素的公共类型是 any 而被当作 [int]
+func (c *Checker) expect(exp ast.Expression, want Type, tok token.Token, context string) {
+	switch want := want.(type) {
+	case *Array:
+		if lit, ok := exp.(*ast.ArrayLiteral); ok {
+			c.expectElements(lit.Elements, want.Element, tok, context)
+			return
+		}
+	case *Set:
+		if lit, ok := exp.(*ast.SetLiteral); ok {
+			c.expectElements(lit.Elements, want.Element, tok, context)
+			return
+		}
+	case *Tuple:
+		if lit, ok := exp.(*ast.TupleLiteral); ok && len(lit.Elements) == len(want.Elements) && !hasSpread(lit.Elements) {
+			for i, e := range lit.Elements {
+				c.expect(e, want.Elements[i], tok, context)
+			}
+			return
+		}
+	case *Hash:
+		if lit, ok := exp.(*ast.HashLiteral); ok {
+			for _, entry := range lit.Entries {
+				if s, ok := entry.Key.(*ast.SpreadExpression); ok && entry.Value == nil { // 展开的哈希整体检查
+					c.expect(s.Value, want, tok, context)
+					continue
+				}
+				c.expect(entry.Key, want.Key, tok, context)
+				c.expect(entry.Value, want.Value, tok, context)
+			}
+			return
+		}
+	}
+
+	if t := c.expression(exp); !assignable(t, want) {
+		c.errorf(tok, "cannot use %s as %s in %s", t, want, context)
+	}
+}
+
+// expectElements 逐个检查数组或集合字面量的元素能否用作 elem 类型，展开的元素取被展开对象的元素类型
+func (c *Checker) expectElements(elements []ast.Expression, elem Type, tok token.Token, context string) {
+	for _, e := range elements {
+		s, ok := e.(*ast.SpreadExpression)
+		if !ok {
+			c.expect(e, elem, tok, context)
+			continue
+		}
+		if t := c.elementType(c.expression(s.Value), s.Token); !assignable(t, elem) {
+			c.errorf(tok, "cannot use %s as %s in %s", t, elem, context)
+		}
+	}
+}
+
+// hasSpread 判断元素中是否有展开
+func hasSpread(elements []ast.Expression) bool {
+	for _, e := range elements {
+		if _, ok := e.(*ast.SpreadExpression); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hashLiteral 检查哈希字面量，返回键和值的公共类型构成的哈希类型
+func (c *Checker) hashLiteral(exp *ast.HashLiteral) Type {
+	var key, value Type
+	for _, entry := range exp.Entries {
+		if s, ok := entry.Key.(*ast.SpreadExpression); ok && entry.Value == nil { // 1. 展开的哈希合并其键值
+			t := c.expression(s.Value)
+			h, ok := t.(*Hash)
+			if !ok {
+				key, value = Any, Any
+				continue
+			}
+			key, value = joinOptional(key, h.Key), joinOptional(value, h.Value)
+			continue
+		}
+		key = joinOptional(key, c.expression(entry.Key))
+		value = joinOptional(value, c.expression(entry.Value))
+	}
+	if key == nil {
+		return &Hash{Key: Any, Value: Any}
+	}
+	return &Hash{Key: key, Value: value}
+}
+
+// joinOptional 与 join 相同，但 a 为 nil 时直接返回 b
+func joinOptional(a, b Type) Type {
+	if a == nil {
+		return b
+	}
+	return join(a, b)
+}
+
+// matchExpression 检查 match 表达式：分支绑定的名字取变体字段的类型，值的类型是各分支类型的公共类型
+func (c *Checker) matchExpression(exp *ast.MatchExpression) Type {
+	subject := c.expression(exp.Subject)
+	var result Type
+	for _, mc := range exp.Cases {
+		fields := c.matchCase(mc, subject)
+		closeScope := c.openScope()
+		for i, name := range mc.Names { // 1. 字段只在分支内可见，类型未知时为 any
+			if name.Value == "_" {
+				continue
+			}
+			if i < len(fields) {
+				c.declare(name.Value, fields[i])
+			} else {
+				c.declare(name.Value, Any)
+			}
+		}
+		body := c.block(mc.Body)
+		closeScope()
+		if result == nil {
+			result = body
+		} else {
+			result = join(result, body)
+		}
+	}
+	if exp.Default != nil { // 2. 没有 default 时不匹配会抛出错误，不产生 nil
+		body := c.scopedBlock(exp.Default)
+		if result == nil {
+			return body
+		}
+		result = join(result, body)
+	}
+	if result == nil {
+		return Any
+	}
+	return result
+}
+
+// matchCase 检查分支的变体能否匹配 subject 类型的值，返回变体字段的类型；变体未知或是泛型时返回 nil
+func (c *Checker) matchCase(mc *ast.MatchCase, subject Type) []Type {
+	t, ok := c.scope.lookup(mc.Variant.Value)
+	if !ok || t == Any {
+		return nil
+	}
+	var want Type = t
+	var fields []Type
+	if fn, ok := t.(*Function); ok { // 1. 有字段的变体以构造函数的形式声明
+		want = fn.Return
+		if len(fn.TypeParams) == 0 {
+			fields = fn.Params
+		}
+		if mc.Names != nil && len(mc.Names) != len(fn.Params) {
+			c.errorf(mc.Token, "wrong number of names in pattern %s: want=%d, got=%d", mc.Variant.Value, len(fn.Params), len(mc.Names))
+		}
+	} else if mc.Names != nil && len(mc.Names) != 0 {
+		c.errorf(mc.Token, "wrong number of names in pattern %s: want=0, got=%d", mc.Variant.Value, len(mc.Names))
+	}
+
+	named, ok := want.(*Named)
+	if ok {
+		_, ok = c.enums[named.Name]
+	}
+	if !ok { // 2. 普通函数和其他值不是变体
+		c.errorf(mc.Token, "case %s in match must be a variant, got %s", mc.Variant.Value, t)
+		return nil
+	}
+	if len(named.Args) == 0 && !assignable(subject, named) { // 3. 泛型枚举的类型实参只有运行时才知道
+		c.errorf(mc.Token, "case %s can never match %s", mc.Variant.Value, subject)
+	}
+	return fields
+}
+
+// comprehensionClause 在当前作用域中声明推导式的循环变量，并检查过滤条件
+func (c *Checker) comprehensionClause(clause *ast.ComprehensionClause) {
+	elem := c.elementType(c.expression(clause.Iterable), clause.Token)
+	if clause.Variable != nil {
+		c.declare(clause.Variable.Value, elem)
+	}
+	for i, name := range clause.Names { // 1. 解构的循环变量按元组的元素声明
+		t := Type(Any)
+		switch e := elem.(type) {
+		case *Tuple:
+			if i < len(e.Elements) {
+				t = e.Elements[i]
+			}
+		case *Array:
+			t = e.Element
+		}
+		c.declare(name.Value, t)
+	}
+	if clause.Condition != nil {
+		c.expression(clause.Condition)
+	}
+}
+
+// elementType 返回遍历类型为 t 的值时得到的元素类型，规则与求值器的 iterate 相同
+func (c *Checker) elementType(t Type, tok token.Token) Type {
+	switch t := t.(type) {
+	case *Array:
+		return t.Element
+	case *Set:
+		return t.Element
+	case *Hash:
+		return t.Key
+	case *Tuple:
+		var elem Type
+		for _, e := range t.Elements {
+			elem = joinOptional(elem, e)
+		}
+		if elem == nil {
+			return Any
+		}
+		return elem
+	case *Function, *Named:
+		c.errorf(tok, "%s is not iterable", t)
+		return Any
+	}
+
+	switch t {
+	case Range, Bytes:
+		return Int
+	case String:
+		return Char
+	case Int, Bool, Nil, Char, Regex, Task:
+		c.errorf(tok, "%s is not iterable", t)
+	}
+	return Any
+}
+
+// indexExpression 检查下标表达式，返回元素的类型
+func (c *Checker) indexExpression(exp *ast.IndexExpression) Type {
+	left := c.expression(exp.Left)
+	index := c.expression(exp.Index)
+
+	var elem, want Type = Any, Int
+	switch l := left.(type) {
+	case *Array:
+		elem = l.Element
+	case *Hash:
+		elem, want = l.Value, l.Key
+	case *Tuple:
+		elem = Any
+		if lit, ok := exp.Index.(*ast.IntegerLiteral); ok && lit.Value >= 0 && lit.Value < int64(len(l.Elements)) { // 1. 常量下标可以确定元组元素的类型
+			elem = l.Elements[lit.Value]
+		}
+	default:
+		switch left {
+		case String:
+			elem = Char
+		case Bytes, Range:
+			elem = Int
+		default:
+			return Any // 2. 其他类型可能通过 __index__ 支持下标，交给求值器处理
+		}
+	}
+
+	if !assignable(index, want) {
+		c.errorf(exp.Token, "cannot use %s as index of %s", index, left)
+	}
+	if exp.Optional {
+		return Any
+	}
+	return elem
+}
+
+// sliceExpression 检查切片表达式，切片的结果与被切片的对象类型相同
+func (c *Checker) sliceExpression(exp *ast.SliceExpression) Type {
+	left := c.expression(exp.Left)
+	for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+		if bound == nil {
+			continue
+		}
+		if t := c.expression(bound); !assignable(t, Int) {
+			c.errorf(exp.Token, "slice bounds must be int, got %s", t)
+		}
+	}
+
+	if exp.Optional {
+		return Any
+	}
+	switch left.(type) {
+	case *Array:
+		return left
+	case *Tuple:
+		return Any
+	}
+	switch left {
+	case String, Bytes:
+		return left
+	}
+	return Any
+}
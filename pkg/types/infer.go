@@ -0,0 +1,722 @@
+package types
+
+import (
+	"strings"
+
+	"punyGo/pkg/ast"
+	"punyGo/pkg/token"
+)
+
+// Inferencer 使用 Hindley–Milner 算法推断整个程序的类型。与 Checker 不同，
+// 没有标注的变量和形参不是 any，而是类型变量，由它们的用法确定；let 和 fn 声明的值按 let 多态泛化
+type Inferencer struct {
+	env        *typeEnv          // 当前作用域
+	enums      map[string]*Named // 已声明的枚举类型
+	enumParams map[string]int    // 泛型枚举的类型参数个数
+	typeParams []map[string]Type // 类型参数的作用域，类型参数推断为类型变量
+	level      int               // 当前的 let 嵌套层次
+	nextID     int               // 下一个类型变量的编号
+	returns    []Type            // 正在推断的函数的返回值类型，栈顶是最内层的函数
+	names      []string          // 按声明顺序排列的顶层名字
+	errors     []string          // 推断中发现的错误
+}
+
+// typeEnv 记录一个作用域中变量的类型模式
+type typeEnv struct {
+	vars  map[string]*Scheme // 变量的类型模式
+	outer *typeEnv           // 外层作用域
+}
+
+// lookup 由内向外查找变量的类型模式
+func (e *typeEnv) lookup(name string) (*Scheme, bool) {
+	for ; e != nil; e = e.outer {
+		if s, ok := e.vars[name]; ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Signature 表示推断出的顶层声明的类型，类型变量已按出现顺序命名为 a、b、c
+type Signature struct {
+	Name        string   // 声明的名字
+	Type        Type     // 推断出的类型
+	Constraints []string // 类型变量上的种类限制，例如 "a: int | string | bytes"
+}
+
+// String 返回 "名字: 类型" 形式的签名，有种类限制时附加 where 子句
+func (s Signature) String() string {
+	out := s.Name + ": " + s.Type.String()
+	if len(s.Constraints) > 0 {
+		out += " where " + strings.Join(s.Constraints, ", ")
+	}
+	return out
+}
+
+// NewInferencer 创建一个类型推断器，内置函数的类型模式已经声明在最外层的作用域中
+func NewInferencer() *Inferencer {
+	in := &Inferencer{
+		env:        &typeEnv{vars: map[string]*Scheme{}},
+		enums:      map[string]*Named{"Result": {Name: "Result"}},
+		enumParams: map[string]int{},
+	}
+	builtins := map[string]Type{
+		"Ok":  &Function{Params: []Type{Any}, Required: 1, Return: in.enums["Result"]},
+		"Err": &Function{Params: []Type{Any}, Required: 1, Return: in.enums["Result"]},
+	}
+	for name, t := range builtinTypes {
+		builtins[name] = t
+	}
+	in.level++ // items 的参数和返回值共用键和值的类型变量，不能由 replaceAny 分别替换
+	key, value := in.fresh(), in.fresh()
+	in.level--
+	builtins["items"] = &Function{Params: []Type{&Hash{Key: key, Value: value}}, Required: 1, Return: &Array{Element: &Tuple{Elements: []Type{key, value}}}}
+	for name, t := range builtins { // 内置函数类型中的 any 替换为泛化的类型变量，例如 len 的类型为 fn(a) -> int
+		in.level++
+		t = in.replaceAny(t)
+		in.level--
+		in.declare(name, in.generalize(t))
+	}
+	return in
+}
+
+// replaceAny 返回把类型中每个 any 替换为新的类型变量后的类型
+func (in *Inferencer) replaceAny(t Type) Type {
+	switch t := t.(type) {
+	case *Array:
+		return &Array{Element: in.replaceAny(t.Element)}
+	case *Set:
+		return &Set{Element: in.replaceAny(t.Element)}
+	case *Hash:
+		return &Hash{Key: in.replaceAny(t.Key), Value: in.replaceAny(t.Value)}
+	case *Tuple:
+		elements := make([]Type, len(t.Elements))
+		for i, e := range t.Elements {
+			elements[i] = in.replaceAny(e)
+		}
+		return &Tuple{Elements: elements}
+	case *Function:
+		f := &Function{Names: t.Names, Required: t.Required}
+		for _, p := range t.Params {
+			f.Params = append(f.Params, in.replaceAny(p))
+		}
+		if t.Rest != nil {
+			f.Rest = in.replaceAny(t.Rest)
+		}
+		f.Return = in.replaceAny(t.Return)
+		return f
+	}
+	if t == Any {
+		return in.fresh()
+	}
+	return t
+}
+
+// Infer 使用新的推断器推断一个程序的类型，返回顶层声明的签名和发现的错误
+func Infer(program *ast.Program) ([]Signature, []string) {
+	return NewInferencer().Infer(program)
+}
+
+// Infer 推断一个程序的类型，返回本次声明的顶层名字的签名和发现的错误
+func (in *Inferencer) Infer(program *ast.Program) ([]Signature, []string) {
+	in.errors = []string{}
+	in.names = []string{}
+
+	ast.Modify(program, func(node ast.Node) ast.Node { // 1. 预先收集所有枚举名
+		if es, ok := node.(*ast.EnumStatement); ok {
+			if _, exists := in.enums[es.Name.Value]; !exists {
+				in.enums[es.Name.Value] = &Named{Name: es.Name.Value}
+				in.enumParams[es.Name.Value] = len(es.TypeParams)
+			}
+		}
+		return node
+	})
+
+	for _, stmt := range program.Statements { // 2. 依次推断每条语句
+		in.statement(stmt)
+	}
+
+	signatures := []Signature{} // 3. 同名的声明只保留最后一个
+	seen := map[string]bool{}
+	for i := len(in.names) - 1; i >= 0; i-- {
+		name := in.names[i]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		scheme, _ := in.env.lookup(name)
+		n := newNamer()
+		signatures = append([]Signature{{Name: name, Type: n.apply(scheme.Type), Constraints: n.constraints()}}, signatures...)
+	}
+	return signatures, in.errors
+}
+
+// errorf 记录一个错误，位置取自 tok
+func (in *Inferencer) errorf(tok token.Token, format string, args ...interface{}) {
+	in.errors = append(in.errors, positioned(tok, format, args...))
+}
+
+// report 记录合一失败的错误，合一给出了更具体的原因时使用该原因
+func (in *Inferencer) report(tok token.Token, err error, format string, args ...interface{}) {
+	if ue, ok := err.(*unifyError); ok && ue.message != "" {
+		in.errorf(tok, "%s", ue.message)
+		return
+	}
+	in.errorf(tok, format, args...)
+}
+
+// expect 要求类型 got 能与 want 合一，context 说明值的用途
+func (in *Inferencer) expect(tok token.Token, got, want Type, context string) {
+	names := describe(got, want)
+	if err := unify(got, want); err != nil {
+		in.report(tok, err, "cannot use %s as %s in %s", names[0], names[1], context)
+	}
+}
+
+// fresh 在当前层次创建一个新的类型变量
+func (in *Inferencer) fresh() *Var {
+	in.nextID++
+	return &Var{ID: in.nextID, Level: in.level}
+}
+
+// generalize 把类型中层次高于当前层次的类型变量泛化，得到类型模式
+func (in *Inferencer) generalize(t Type) *Scheme {
+	scheme := &Scheme{Type: t}
+	seen := map[*Var]bool{}
+	walk(t, func(v *Var) {
+		if v.Level > in.level && !seen[v] {
+			seen[v] = true
+			scheme.Vars = append(scheme.Vars, v)
+		}
+	})
+	return scheme
+}
+
+// instantiate 把类型模式中被泛化的类型变量替换为新的类型变量
+func (in *Inferencer) instantiate(s *Scheme) Type {
+	if len(s.Vars) == 0 {
+		return s.Type
+	}
+	fresh := map[*Var]*Var{}
+	for _, v := range s.Vars {
+		w := in.fresh()
+		w.Kinds, w.Op, w.Param = v.Kinds, v.Op, v.Param
+		fresh[v] = w
+	}
+	return substitute(s.Type, func(v *Var) Type {
+		if w, ok := fresh[v]; ok {
+			return w
+		}
+		return v
+	})
+}
+
+// declare 在当前作用域中声明变量的类型模式
+func (in *Inferencer) declare(name string, s *Scheme) {
+	in.env.vars[name] = s
+}
+
+// declareMono 在当前作用域中声明不泛化的变量
+func (in *Inferencer) declareMono(name string, t Type) {
+	in.declare(name, &Scheme{Type: t})
+}
+
+// declareUnknown 声明类型未知的变量，每次使用时都得到新的类型变量
+func (in *Inferencer) declareUnknown(name string) {
+	in.level++
+	v := in.fresh()
+	in.level--
+	in.declare(name, &Scheme{Vars: []*Var{v}, Type: v})
+}
+
+// openScope 进入一个新的作用域，返回用于离开该作用域的函数
+func (in *Inferencer) openScope() func() {
+	outer := in.env
+	in.env = &typeEnv{vars: map[string]*Scheme{}, outer: outer}
+	return func() { in.env = outer }
+}
+
+// bindTypeParams 为类型参数创建类型变量并进入类型参数的作用域，返回这些类型变量和用于离开该作用域的函数
+func (in *Inferencer) bindTypeParams(ids []*ast.Identifier) ([]Type, func()) {
+	vars := []Type{}
+	scope := map[string]Type{}
+	for _, id := range ids {
+		v := in.fresh()
+		v.Param = id.Value
+		vars = append(vars, v)
+		scope[id.Value] = v
+	}
+	in.typeParams = append(in.typeParams, scope)
+	return vars, func() { in.typeParams = in.typeParams[:len(in.typeParams)-1] }
+}
+
+// annotation 把类型标注转换为类型，其中的 any 转换为新的类型变量
+func (in *Inferencer) annotation(te ast.TypeExpression) Type {
+	switch te := te.(type) {
+	case *ast.NamedType:
+		for i := len(in.typeParams) - 1; i >= 0 && len(te.Arguments) == 0; i-- { // 1. 类型参数优先于同名的类型
+			if t, ok := in.typeParams[i][te.Name]; ok {
+				return t
+			}
+		}
+		if t, ok := in.enums[te.Name]; ok {
+			if len(te.Arguments) == 0 {
+				return t
+			}
+			if want := in.enumParams[te.Name]; want != len(te.Arguments) { // 2. 泛型枚举的类型实参个数必须与类型参数相同
+				in.errorf(te.Token, "type %s expects %d type arguments, got %d", te.Name, want, len(te.Arguments))
+				return t
+			}
+			named := &Named{Name: te.Name}
+			for _, a := range te.Arguments {
+				named.Args = append(named.Args, in.annotation(a))
+			}
+			return named
+		}
+		if len(te.Arguments) > 0 {
+			in.errorf(te.Token, "type %s does not take type arguments", te.Name)
+			return in.fresh()
+		}
+		if te.Name == "any" {
+			return in.fresh()
+		}
+		if t, ok := basicTypes[te.Name]; ok {
+			return t
+		}
+		in.errorf(te.Token, "unknown type %s", te.Name)
+	case *ast.ArrayType:
+		return &Array{Element: in.annotation(te.Element)}
+	case *ast.HashType:
+		return &Hash{Key: in.annotation(te.Key), Value: in.annotation(te.Value)}
+	case *ast.SetType:
+		return &Set{Element: in.annotation(te.Element)}
+	case *ast.TupleType:
+		elements := make([]Type, len(te.Elements))
+		for i, e := range te.Elements {
+			elements[i] = in.annotation(e)
+		}
+		return &Tuple{Elements: elements}
+	case *ast.FunctionType:
+		fn := &Function{Required: len(te.Parameters), Return: in.fresh()}
+		for _, p := range te.Parameters {
+			fn.Params = append(fn.Params, in.annotation(p))
+		}
+		if te.Return != nil {
+			fn.Return = in.annotation(te.Return)
+		}
+		return fn
+	}
+	return in.fresh()
+}
+
+// statement 推断一条语句，语句的值不被使用
+func (in *Inferencer) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		if ie, ok := stmt.Expression.(*ast.IfExpression); ok { // 1. 作为语句的 if 的两个分支不必类型相同
+			in.ifExpression(ie, false)
+			return
+		}
+		if me, ok := stmt.Expression.(*ast.MatchExpression); ok { // 1.1. match 同理
+			in.matchExpression(me, false)
+			return
+		}
+		in.expression(stmt.Expression)
+
+	case *ast.LetStatement:
+		in.letStatement(stmt)
+
+	case *ast.FunctionStatement:
+		fl := stmt.Function
+		in.level++
+		self := in.fresh() // 1. 函数体中递归调用时使用单态的类型
+		in.declareMono(fl.Name, self)
+		fn := in.function(fl, nil)
+		if err := unify(self, fn); err != nil {
+			in.report(fl.Token, err, "recursive use of %s does not match its definition", fl.Name)
+		}
+		in.level--
+		in.declare(fl.Name, in.generalize(fn)) // 2. 函数体之后的代码使用泛化的类型
+		in.record(fl.Name)
+
+	case *ast.ReturnStatement:
+		t := in.expression(stmt.ReturnValue)
+		if n := len(in.returns); n > 0 {
+			in.expect(stmt.Token, t, in.returns[n-1], "return")
+		}
+
+	case *ast.EnumStatement:
+		named := in.enums[stmt.Name.Value]
+		for _, v := range stmt.Variants { // 1. 没有标注的字段可以是任何类型，构造函数对这些字段是多态的
+			in.level++
+			params, closeParams := in.bindTypeParams(stmt.TypeParams)
+			result := named
+			if len(params) > 0 { // 2. 泛型枚举的构造函数返回以类型参数为实参的枚举类型
+				result = &Named{Name: named.Name, Args: params}
+			}
+			var t Type = result
+			if len(v.Fields) > 0 {
+				fn := &Function{Required: len(v.Fields), Return: result}
+				for i, f := range v.Fields {
+					var field Type = in.fresh()
+					if i < len(v.FieldTypes) && v.FieldTypes[i] != nil {
+						field = in.annotation(v.FieldTypes[i])
+					}
+					fn.Params = append(fn.Params, field)
+					fn.Names = append(fn.Names, f.Value)
+				}
+				t = fn
+			}
+			closeParams()
+			in.level--
+			in.declare(v.Name.Value, in.generalize(t))
+		}
+		in.declareUnknown(stmt.Name.Value)
+
+	case *ast.ImplStatement:
+		var receiver Type
+		if named, ok := in.enums[stmt.Name.Value]; ok {
+			receiver = named
+		} else {
+			in.errorf(stmt.Name.Token, "unknown type %s", stmt.Name.Value)
+		}
+		for _, m := range stmt.Methods {
+			in.level++
+			in.function(m, receiver)
+			in.level--
+		}
+
+	case *ast.BlockStatement:
+		in.scopedStatements(stmt)
+
+	case *ast.ForStatement:
+		elem := in.elementType(in.expression(stmt.Iterable), stmt.Token)
+		defer in.openScope()()
+		in.declareMono(stmt.Variable.Value, elem)
+		in.statements(stmt.Body)
+
+	case *ast.TryStatement:
+		in.scopedStatements(stmt.Block)
+		if stmt.Catch != nil {
+			closeScope := in.openScope()
+			if stmt.CatchParam != nil {
+				in.declareUnknown(stmt.CatchParam.Value)
+			}
+			in.statements(stmt.Catch)
+			closeScope()
+		}
+		in.scopedStatements(stmt.Finally)
+
+	case *ast.ThrowStatement:
+		in.expression(stmt.Value)
+
+	case *ast.YieldStatement:
+		in.expression(stmt.Value)
+
+	case *ast.DeferStatement:
+		in.expression(stmt.Call)
+
+	case *ast.SelectStatement:
+		for _, sc := range stmt.Cases {
+			in.expression(sc.Comm)
+			closeScope := in.openScope()
+			if sc.Name != nil {
+				in.declareUnknown(sc.Name.Value)
+			}
+			in.statements(sc.Body)
+			closeScope()
+		}
+		in.scopedStatements(stmt.Default)
+
+	case *ast.ImportStatement:
+		in.declareUnknown(stmt.Alias.Value)
+
+	case *ast.ExportStatement:
+		in.statement(stmt.Statement)
+	}
+}
+
+// letStatement 推断 let 语句，变量的类型按 let 多态泛化
+func (in *Inferencer) letStatement(stmt *ast.LetStatement) {
+	in.level++
+	var self Type
+	if _, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil { // 1. 函数先绑定到变量名，函数体中递归调用时使用单态的类型
+		self = in.fresh()
+		in.declareMono(stmt.Name.Value, self)
+	}
+	t := in.expression(stmt.Value)
+	if self != nil {
+		if err := unify(self, t); err != nil {
+			in.report(stmt.Token, err, "recursive use of %s does not match its definition", stmt.Name.Value)
+		}
+	}
+	if stmt.Type != nil {
+		in.expect(stmt.Token, t, in.annotation(stmt.Type), "let "+letNames(stmt))
+	}
+	in.level--
+
+	if stmt.Name != nil {
+		in.declare(stmt.Name.Value, in.generalize(t))
+		in.record(stmt.Name.Value)
+		return
+	}
+	for i, elem := range in.unpack(t, len(stmt.Names), stmt.Token) { // 2. 解构赋值的每个变量分别泛化
+		in.declare(stmt.Names[i].Value, in.generalize(elem))
+		in.record(stmt.Names[i].Value)
+	}
+}
+
+// record 记录顶层声明的名字，用于输出签名
+func (in *Inferencer) record(name string) {
+	if in.env.outer == nil {
+		in.names = append(in.names, name)
+	}
+}
+
+// unpack 返回把类型为 t 的值解构为 n 个变量时各变量的类型
+func (in *Inferencer) unpack(t Type, n int, tok token.Token) []Type {
+	elems := make([]Type, n)
+	switch t := prune(t).(type) {
+	case *Tuple:
+		if len(t.Elements) == n {
+			copy(elems, t.Elements)
+			return elems
+		}
+		in.errorf(tok, "cannot unpack %d values into %d names", len(t.Elements), n)
+	case *Array:
+		for i := range elems {
+			elems[i] = t.Element
+		}
+		return elems
+	case *Var: // 1. 未知的值按元组解构
+		for i := range elems {
+			elems[i] = in.fresh()
+		}
+		in.expect(tok, t, &Tuple{Elements: elems}, "destructuring")
+		return elems
+	default:
+		if t != Any {
+			in.errorf(tok, "cannot unpack %s", describe(t)[0])
+		}
+	}
+	for i := range elems {
+		elems[i] = in.fresh()
+	}
+	return elems
+}
+
+// statements 在当前作用域中推断语句块，语句块的值不被使用
+func (in *Inferencer) statements(block *ast.BlockStatement) {
+	for _, stmt := range block.Statements {
+		in.statement(stmt)
+	}
+}
+
+// scopedStatements 在新的作用域中推断语句块，语句块可以为 nil
+func (in *Inferencer) scopedStatements(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	defer in.openScope()()
+	in.statements(block)
+}
+
+// block 在新的作用域中推断语句块并返回其值的类型：最后一条语句是表达式时为其类型，
+// 以 return 或 throw 结束时不会产生值，为新的类型变量，否则为 nil
+func (in *Inferencer) block(block *ast.BlockStatement) Type {
+	defer in.openScope()()
+	if len(block.Statements) == 0 {
+		return Nil
+	}
+	last := len(block.Statements) - 1
+	for _, stmt := range block.Statements[:last] {
+		in.statement(stmt)
+	}
+	switch stmt := block.Statements[last].(type) {
+	case *ast.ExpressionStatement:
+		return in.expression(stmt.Expression)
+	case *ast.ReturnStatement, *ast.ThrowStatement:
+		in.statement(stmt)
+		return in.fresh()
+	default:
+		in.statement(stmt)
+		return Nil
+	}
+}
+
+// function 推断函数字面量的类型，receiver 不为 nil 时作为没有标注的第一个形参的类型
+func (in *Inferencer) function(fl *ast.FunctionLiteral, receiver Type) *Function {
+	fn := &Function{}
+	defer in.openScope()()
+	params, closeParams := in.bindTypeParams(fl.TypeParams)
+	defer closeParams()
+	defer in.checkTypeParams(fl, params)
+
+	for i, p := range fl.Parameters { // 1. 没有标注的形参是新的类型变量
+		var t Type
+		switch {
+		case i < len(fl.ParamTypes) && fl.ParamTypes[i] != nil:
+			t = in.annotation(fl.ParamTypes[i])
+		case i == 0 && receiver != nil:
+			t = receiver
+		default:
+			t = in.fresh()
+		}
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			in.expect(p.Token, in.expression(fl.Defaults[i]), t, "default of parameter "+p.Value)
+		} else {
+			fn.Required++
+		}
+		in.declareMono(p.Value, t)
+		fn.Params = append(fn.Params, t)
+		fn.Names = append(fn.Names, p.Value)
+	}
+
+	if fl.Rest != nil { // 2. 可变参数总是数组
+		rest := &Array{Element: in.fresh()}
+		if fl.RestType != nil {
+			ann := in.annotation(fl.RestType)
+			if err := unify(rest, ann); err != nil {
+				in.errorf(fl.Rest.Token, "variadic parameter %s must have an array type, got %s", fl.Rest.Value, describe(ann)[0])
+			}
+		}
+		in.declareMono(fl.Rest.Value, rest)
+		fn.Rest = rest
+	}
+
+	fn.Return = in.fresh()
+	if fl.ReturnType != nil {
+		fn.Return = in.annotation(fl.ReturnType)
+	}
+
+	if fl.IsGenerator { // 3. 生成器中的 return 只是结束迭代，调用生成器函数得到的是生成器
+		in.returns = append(in.returns, in.fresh())
+		in.scopedStatements(fl.Body)
+		in.returns = in.returns[:len(in.returns)-1]
+		fn.Return = Generator
+		return fn
+	}
+
+	in.returns = append(in.returns, fn.Return)
+	body := in.block(fl.Body)
+	in.returns = in.returns[:len(in.returns)-1]
+	in.expect(fl.Token, body, fn.Return, "return of "+functionName(fl)) // 4. 最后一个表达式是隐式的返回值
+	return fn
+}
+
+// checkTypeParams 检查推断结束后泛型函数的类型参数仍然是互不相同的类型变量，
+// 否则函数体把类型参数当作了具体的类型
+func (in *Inferencer) checkTypeParams(fl *ast.FunctionLiteral, params []Type) {
+	seen := map[*Var]string{}
+	for i, t := range params {
+		name := fl.TypeParams[i].Value
+		v, ok := prune(t).(*Var)
+		if !ok {
+			in.errorf(fl.TypeParams[i].Token, "type parameter %s of %s cannot be %s", name, functionName(fl), describe(t)[0])
+			continue
+		}
+		if other, dup := seen[v]; dup {
+			in.errorf(fl.TypeParams[i].Token, "type parameters %s and %s of %s must be the same type", other, name, functionName(fl))
+			continue
+		}
+		seen[v] = name
+	}
+}
+
+// ifExpression 推断 if 表达式。value 为 true 时 if 的值被使用，两个分支的类型必须相同
+func (in *Inferencer) ifExpression(exp *ast.IfExpression, value bool) Type {
+	in.expression(exp.Condition)
+	if !value {
+		in.scopedStatements(exp.Consequence)
+		in.scopedStatements(exp.Alternative)
+		return Nil
+	}
+
+	consequence := in.block(exp.Consequence)
+	if exp.Alternative == nil { // 1. 没有 else 时条件不成立的值是 nil，这里不要求两者相同
+		return Nil
+	}
+	alternative := in.block(exp.Alternative)
+	names := describe(consequence, alternative)
+	if err := unify(consequence, alternative); err != nil {
+		in.report(exp.Token, err, "if branches have different types: %s and %s", names[0], names[1])
+	}
+	return consequence
+}
+
+// matchExpression 推断 match 表达式：被匹配的值与分支变体所属的枚举类型合一，
+// 分支绑定的名字取变体字段的类型。value 为 false 时表达式的值不被使用，各分支不必类型相同
+func (in *Inferencer) matchExpression(exp *ast.MatchExpression, value bool) Type {
+	subject := in.expression(exp.Subject)
+	var result Type = Nil
+	if value {
+		result = in.fresh()
+	}
+	branch := func(tok token.Token, body *ast.BlockStatement) {
+		if !value {
+			in.statements(body)
+			return
+		}
+		t := in.block(body)
+		names := describe(result, t)
+		if err := unify(result, t); err != nil {
+			in.report(tok, err, "match branches have different types: %s and %s", names[0], names[1])
+		}
+	}
+
+	for _, mc := range exp.Cases {
+		fields := in.matchCase(mc, subject)
+		closeScope := in.openScope()
+		for i, name := range mc.Names { // 1. 字段只在分支内可见
+			if name.Value == "_" {
+				continue
+			}
+			if i < len(fields) {
+				in.declareMono(name.Value, fields[i])
+			} else {
+				in.declareMono(name.Value, in.fresh())
+			}
+		}
+		branch(mc.Token, mc.Body)
+		closeScope()
+	}
+	if exp.Default != nil {
+		closeScope := in.openScope()
+		branch(exp.Token, exp.Default)
+		closeScope()
+	}
+	return result
+}
+
+// matchCase 要求分支的变体能匹配 subject 类型的值，返回变体字段的类型；变体未知时返回 nil
+func (in *Inferencer) matchCase(mc *ast.MatchCase, subject Type) []Type {
+	s, ok := in.env.lookup(mc.Variant.Value)
+	if !ok {
+		return nil
+	}
+	t := in.instantiate(s)
+	want := t
+	var fields []Type
+	if fn, ok := prune(t).(*Function); ok { // 1. 有字段的变体以构造函数的形式声明
+		want, fields = fn.Return, fn.Params
+		if mc.Names != nil && len(mc.Names) != len(fn.Params) {
+			in.errorf(mc.Token, "wrong number of names in pattern %s: want=%d, got=%d", mc.Variant.Value, len(fn.Params), len(mc.Names))
+		}
+	} else if mc.Names != nil && len(mc.Names) != 0 {
+		in.errorf(mc.Token, "wrong number of names in pattern %s: want=0, got=%d", mc.Variant.Value, len(mc.Names))
+	}
+
+	named, ok := prune(want).(*Named)
+	if ok {
+		_, ok = in.enums[named.Name]
+	}
+	if !ok { // 2. 普通函数和其他值不是变体
+		in.errorf(mc.Token, "case %s in match must be a variant, got %s", mc.Variant.Value, describe(t)[0])
+		return nil
+	}
+	name := describe(subject)[0]
+	if err := unify(subject, want); err != nil {
+		in.report(mc.Token, err, "case %s can never match %s", mc.Variant.Value, name)
+	}
+	return fields
+}
@@ -0,0 +1,534 @@
+package types
+
+import (
+	"punyGo/pkg/ast"
+	"punyGo/pkg/token"
+)
+
+// expression 推断表达式的类型
+func (in *Inferencer) expression(exp ast.Expression) Type {
+	switch exp := exp.(type) {
+	case nil:
+		return Nil
+
+	// 字面量
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.Boolean:
+		return Bool
+	case *ast.StringLiteral:
+		return String
+	case *ast.InterpolatedString:
+		for _, part := range exp.Parts {
+			in.expression(part)
+		}
+		return String
+	case *ast.BytesLiteral:
+		return Bytes
+	case *ast.CharLiteral:
+		return Char
+	case *ast.RegexLiteral:
+		return Regex
+	case *ast.NilLiteral:
+		return Nil
+
+	case *ast.Identifier:
+		if s, ok := in.env.lookup(exp.Value); ok {
+			return in.instantiate(s)
+		}
+		return in.fresh() // 未声明的标识符可能来自模块或运行时环境，类型未知
+
+	case *ast.PrefixExpression:
+		return in.prefixExpression(exp)
+	case *ast.InfixExpression:
+		return in.infixExpression(exp)
+	case *ast.PostfixExpression:
+		in.expression(exp.Left)
+		return in.fresh()
+
+	case *ast.IfExpression:
+		return in.ifExpression(exp, true)
+	case *ast.MatchExpression:
+		return in.matchExpression(exp, true)
+
+	case *ast.FunctionLiteral:
+		return in.function(exp, nil)
+	case *ast.MacroLiteral:
+		return in.fresh()
+
+	case *ast.CallExpression:
+		return in.callExpression(exp)
+	case *ast.SpawnExpression:
+		in.expression(exp.Call)
+		return Task
+
+	case *ast.ArrayLiteral:
+		return &Array{Element: in.elements(exp.Token, exp.Elements, "array")}
+	case *ast.SetLiteral:
+		return &Set{Element: in.elements(exp.Token, exp.Elements, "set")}
+	case *ast.TupleLiteral:
+		elements := []Type{}
+		for _, e := range exp.Elements {
+			if s, ok := e.(*ast.SpreadExpression); ok { // 1. 有展开时元素个数只有运行时才知道
+				in.expression(s.Value)
+				return in.fresh()
+			}
+			elements = append(elements, in.expression(e))
+		}
+		return &Tuple{Elements: elements}
+	case *ast.HashLiteral:
+		return in.hashLiteral(exp)
+	case *ast.RangeExpression:
+		for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+			if bound != nil {
+				in.expect(exp.Token, in.expression(bound), Int, "range bounds")
+			}
+		}
+		return Range
+
+	case *ast.ListComprehension:
+		defer in.openScope()()
+		in.comprehensionClause(exp.Clause)
+		return &Array{Element: in.expression(exp.Element)}
+	case *ast.HashComprehension:
+		defer in.openScope()()
+		in.comprehensionClause(exp.Clause)
+		return &Hash{Key: in.expression(exp.Key), Value: in.expression(exp.Value)}
+
+	case *ast.MemberExpression:
+		if h, ok := prune(in.expression(exp.Object)).(*Hash); ok { // 1. 哈希按同名的字符串键取值
+			in.expect(exp.Token, h.Key, String, "hash member "+exp.Property.Value)
+			return h.Value
+		}
+		return in.fresh()
+	case *ast.IndexExpression:
+		return in.indexExpression(exp)
+	case *ast.SliceExpression:
+		left := in.expression(exp.Left)
+		for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+			if bound != nil {
+				in.expect(exp.Token, in.expression(bound), Int, "slice bounds")
+			}
+		}
+		if _, ok := prune(left).(*Tuple); ok {
+			return in.fresh()
+		}
+		return left
+
+	case *ast.SpreadExpression:
+		in.expression(exp.Value)
+		return in.fresh()
+	case *ast.KeywordArgument:
+		in.expression(exp.Value)
+		return in.fresh()
+	}
+	return in.fresh()
+}
+
+// elements 推断数组或集合字面量的元素，所有元素必须类型相同
+func (in *Inferencer) elements(tok token.Token, elements []ast.Expression, what string) Type {
+	elem := Type(in.fresh())
+	for _, e := range elements {
+		var t Type
+		if s, ok := e.(*ast.SpreadExpression); ok { // 1. 展开的元素取被展开对象的元素类型
+			t = in.elementType(in.expression(s.Value), s.Token)
+		} else {
+			t = in.expression(e)
+		}
+		names := describe(elem, t)
+		if err := unify(elem, t); err != nil {
+			in.report(tok, err, "%s elements have different types: %s and %s", what, names[0], names[1])
+		}
+	}
+	return elem
+}
+
+// hashLiteral 推断哈希字面量，所有键的类型相同，所有值的类型也相同
+func (in *Inferencer) hashLiteral(exp *ast.HashLiteral) Type {
+	hash := &Hash{Key: in.fresh(), Value: in.fresh()}
+	for _, entry := range exp.Entries {
+		if s, ok := entry.Key.(*ast.SpreadExpression); ok && entry.Value == nil { // 1. 展开的哈希与字面量类型相同
+			in.expect(s.Token, in.expression(s.Value), hash, "hash spread")
+			continue
+		}
+		key, value := in.expression(entry.Key), in.expression(entry.Value)
+		names := describe(hash.Key, key, hash.Value, value)
+		if err := unify(hash.Key, key); err != nil {
+			in.report(exp.Token, err, "hash keys have different types: %s and %s", names[0], names[1])
+		}
+		if err := unify(hash.Value, value); err != nil {
+			in.report(exp.Token, err, "hash values have different types: %s and %s", names[2], names[3])
+		}
+	}
+	return hash
+}
+
+// comprehensionClause 在当前作用域中声明推导式的循环变量，并推断过滤条件
+func (in *Inferencer) comprehensionClause(clause *ast.ComprehensionClause) {
+	elem := in.elementType(in.expression(clause.Iterable), clause.Token)
+	if clause.Variable != nil {
+		in.declareMono(clause.Variable.Value, elem)
+	}
+	if len(clause.Names) > 0 {
+		for i, t := range in.unpack(elem, len(clause.Names), clause.Token) {
+			in.declareMono(clause.Names[i].Value, t)
+		}
+	}
+	if clause.Condition != nil {
+		in.expression(clause.Condition)
+	}
+}
+
+// elementType 返回遍历类型为 t 的值时得到的元素类型，类型未知的值按数组遍历
+func (in *Inferencer) elementType(t Type, tok token.Token) Type {
+	switch p := prune(t).(type) {
+	case *Array:
+		return p.Element
+	case *Set:
+		return p.Element
+	case *Hash:
+		return p.Key
+	case *Tuple:
+		return in.fresh()
+	case *Var:
+		elem := in.fresh()
+		in.expect(tok, p, &Array{Element: elem}, "for loop")
+		return elem
+	case *Function, *Named:
+		in.errorf(tok, "%s is not iterable", describe(p)[0])
+		return in.fresh()
+	}
+
+	switch prune(t) {
+	case Range, Bytes:
+		return Int
+	case String:
+		return Char
+	case Int, Bool, Nil, Char, Regex, Task:
+		in.errorf(tok, "%s is not iterable", t)
+	}
+	return in.fresh()
+}
+
+// prefixExpression 推断前缀表达式，- 只作用于整数，! 只作用于布尔值
+func (in *Inferencer) prefixExpression(exp *ast.PrefixExpression) Type {
+	right := in.expression(exp.Right)
+	want := Int
+	if exp.Operator == "!" {
+		want = Bool
+	}
+	names := describe(right)
+	if err := unify(right, want); err != nil {
+		in.report(exp.Token, err, "unknown operator: %s%s", exp.Operator, names[0])
+	}
+	return want
+}
+
+// infixExpression 推断中缀表达式。两个操作数的类型必须相同，操作符对类型种类的要求记录在类型变量上，
+// 例如 fn(a, b) { a + b } 的类型为 fn(a, a) -> a where a: int | string | bytes
+func (in *Inferencer) infixExpression(exp *ast.InfixExpression) Type {
+	left := in.expression(exp.Left)
+	right := in.expression(exp.Right)
+	op := exp.Operator
+
+	switch op { // 1. 与操作数类型无关的操作符
+	case "in":
+		return Bool
+	case "is":
+		if f, ok := prune(right).(*Function); ok { // 变体构造函数的返回值类型就是左侧的枚举类型
+			right = f.Return
+		}
+		if _, ok := prune(right).(*Named); ok {
+			in.expect(exp.Token, left, right, "left operand of is")
+		}
+		return Bool
+	case "=~":
+		in.expect(exp.Token, right, Regex, "right operand of =~")
+		in.constrain(exp, left, right, "string", "bytes")
+		return Bool
+	case "??":
+		if prune(left) == Nil {
+			return right
+		}
+		in.operands(exp, left, right)
+		return left
+	}
+
+	_, leftNamed := prune(left).(*Named)
+	_, rightNamed := prune(right).(*Named)
+	if leftNamed || rightNamed { // 2. 枚举值可以通过特殊方法重载操作符，结果类型未知
+		if op == "==" || op == "!=" {
+			return Bool
+		}
+		return in.fresh()
+	}
+
+	switch op { // 3. 同类型的操作数按类型支持的操作符运算
+	case "==", "!=":
+		if prune(left) != Nil && prune(right) != Nil { // 任何值都可以与 nil 比较
+			in.operands(exp, left, right)
+		}
+		return Bool
+	case "<", ">":
+		in.operands(exp, left, right)
+		in.constrain(exp, left, right, "int", "char")
+		return Bool
+	case "+":
+		in.operands(exp, left, right)
+		in.constrain(exp, left, right, "int", "string", "bytes")
+		return left
+	case "|", "&", "^":
+		in.operands(exp, left, right)
+		in.constrain(exp, left, right, "int", "set")
+		return left
+	case "-":
+		in.operands(exp, left, right)
+		in.constrain(exp, left, right, "int", "set")
+		return left
+	case "*", "/", "%":
+		in.operands(exp, left, right)
+		in.constrain(exp, left, right, "int")
+		return left
+	}
+	return in.fresh()
+}
+
+// operands 要求中缀表达式的两个操作数类型相同
+func (in *Inferencer) operands(exp *ast.InfixExpression, left, right Type) {
+	names := describe(left, right)
+	if err := unify(left, right); err != nil {
+		in.report(exp.Token, err, "type mismatch: %s %s %s", names[0], exp.Operator, names[1])
+	}
+}
+
+// constrain 要求左操作数的类型属于 kinds 中的一种。类型未知时把要求记录在类型变量上，
+// 只剩一种基本类型可选时直接确定为该类型
+func (in *Inferencer) constrain(exp *ast.InfixExpression, left, right Type, kinds ...string) {
+	switch t := prune(left).(type) {
+	case *Var:
+		merged := intersectKinds(t.Kinds, kinds)
+		if len(merged) == 0 {
+			in.errorf(exp.Token, "no type supports both %s and %s", t.Op, exp.Operator)
+			return
+		}
+		if t.Kinds == nil {
+			t.Op = exp.Operator
+		}
+		t.Kinds = merged
+		if basic, ok := basicTypes[merged[0]]; ok && len(merged) == 1 {
+			t.Kinds = nil
+			unify(t, basic)
+		}
+	case *Named:
+	default:
+		if t == Any {
+			return
+		}
+		for _, k := range kinds {
+			if kind(t) == k {
+				return
+			}
+		}
+		names := describe(left, right)
+		in.errorf(exp.Token, "unknown operator: %s %s %s", names[0], exp.Operator, names[1])
+	}
+}
+
+// callExpression 推断函数调用。被调用的函数类型未知时，根据实参构造函数类型与之合一
+func (in *Inferencer) callExpression(exp *ast.CallExpression) Type {
+	if ident, ok := exp.Function.(*ast.Identifier); ok { // 1. quote 的实参是未求值的 AST，不做推断
+		if _, declared := in.env.lookup(ident.Value); !declared && (ident.Value == "quote" || ident.Value == "unquote") {
+			return in.fresh()
+		}
+	}
+
+	callee := in.expression(exp.Function)
+	positional := []Type{}
+	keywords := []*ast.KeywordArgument{}
+	keywordTypes := []Type{}
+	spread := false
+	for _, arg := range exp.Arguments {
+		switch arg := arg.(type) {
+		case *ast.KeywordArgument:
+			keywords = append(keywords, arg)
+			keywordTypes = append(keywordTypes, in.expression(arg.Value))
+		case *ast.SpreadExpression:
+			in.expression(arg.Value)
+			spread = true
+		default:
+			t := in.expression(arg)
+			if !spread {
+				positional = append(positional, t)
+			}
+		}
+	}
+
+	name := calleeName(exp.Function)
+	switch fn := prune(callee).(type) {
+	case *Var: // 2. 类型未知的函数
+		result := in.fresh()
+		if len(keywords) > 0 || spread {
+			return result
+		}
+		ft := &Function{Params: positional, Required: len(positional), Return: result}
+		names := describe(fn, ft)
+		if err := unify(fn, ft); err != nil {
+			in.report(exp.Token, err, "cannot call %s of type %s as %s", name, names[0], names[1])
+		}
+		return result
+
+	case *Function: // 3. 类型已知的函数，检查实参个数并合一每个实参
+		got := len(positional) + len(keywords)
+		if !spread && (got < fn.Required || (fn.Rest == nil && got > len(fn.Params))) {
+			in.errorf(exp.Token, "wrong number of arguments for %s: want=%s, got=%d", name, arity(fn), got)
+			return fn.Return
+		}
+		for i, t := range positional {
+			switch {
+			case i < len(fn.Params):
+				in.expectArgument(exp.Token, t, fn.Params[i], parameterLabel(fn, i)+" of "+name, name)
+			case fn.Rest != nil:
+				if rest, ok := prune(fn.Rest).(*Array); ok {
+					in.expectArgument(exp.Token, t, rest.Element, "variadic parameter of "+name, name)
+				}
+			}
+		}
+		for i, kw := range keywords {
+			idx := -1
+			for j, n := range fn.Names {
+				if n == kw.Name.Value {
+					idx = j
+				}
+			}
+			if idx < 0 {
+				if fn.Names != nil {
+					in.errorf(kw.Token, "%s got an unexpected keyword argument %s", name, kw.Name.Value)
+				}
+				continue
+			}
+			in.expectArgument(kw.Token, keywordTypes[i], fn.Params[idx], "parameter "+kw.Name.Value+" of "+name, name)
+		}
+		return fn.Return
+	}
+
+	if callee != Any {
+		in.errorf(exp.Token, "not a function: %s", describe(callee)[0])
+	}
+	return in.fresh()
+}
+
+// expectArgument 要求实参类型 got 能与形参类型 param 合一。形参中由类型参数产生的类型变量先替换为新的类型变量，
+// 合一实参后再与原来的类型变量合一，因此同一个类型参数的两个实例冲突时，错误信息指出该类型参数和冲突的两个类型
+func (in *Inferencer) expectArgument(tok token.Token, got, param Type, context, name string) {
+	copies := map[*Var]*Var{}
+	order := []*Var{}
+	split := in.splitTypeParams(param, copies, &order)
+	in.expect(tok, got, split, context)
+	for _, v := range order {
+		names := describe(v, copies[v])
+		if err := unify(v, copies[v]); err != nil {
+			in.report(tok, err, "conflicting instantiations of %s in call to %s: %s and %s", v.Param, name, names[0], names[1])
+		}
+	}
+}
+
+// splitTypeParams 复制类型，其中由类型参数产生的类型变量无论是否已经确定，都替换为新的类型变量，copies 记录替换关系
+func (in *Inferencer) splitTypeParams(t Type, copies map[*Var]*Var, order *[]*Var) Type {
+	if v, ok := t.(*Var); ok {
+		switch {
+		case v.Param != "": // 1. 同一个类型变量只替换一次
+			if w, ok := copies[v]; ok {
+				return w
+			}
+			w := in.fresh()
+			w.Kinds, w.Op = v.Kinds, v.Op
+			copies[v] = w
+			*order = append(*order, v)
+			return w
+		case v.Instance != nil: // 2. 已确定的普通类型变量继续复制其类型
+			return in.splitTypeParams(v.Instance, copies, order)
+		}
+		return v
+	}
+
+	split := func(t Type) Type { return in.splitTypeParams(t, copies, order) }
+	switch t := t.(type) {
+	case *Array:
+		return &Array{Element: split(t.Element)}
+	case *Set:
+		return &Set{Element: split(t.Element)}
+	case *Hash:
+		return &Hash{Key: split(t.Key), Value: split(t.Value)}
+	case *Tuple:
+		elements := make([]Type, len(t.Elements))
+		for i, e := range t.Elements {
+			elements[i] = split(e)
+		}
+		return &Tuple{Elements: elements}
+	case *Function:
+		f := &Function{Names: t.Names, Required: t.Required}
+		for _, p := range t.Params {
+			f.Params = append(f.Params, split(p))
+		}
+		if t.Rest != nil {
+			f.Rest = split(t.Rest)
+		}
+		f.Return = split(t.Return)
+		return f
+	case *Named:
+		if len(t.Args) == 0 {
+			return t
+		}
+		n := &Named{Name: t.Name}
+		for _, a := range t.Args {
+			n.Args = append(n.Args, split(a))
+		}
+		return n
+	}
+	return t
+}
+
+// indexExpression 推断下标表达式。类型未知的对象用整数下标访问时按数组处理，否则按哈希处理
+func (in *Inferencer) indexExpression(exp *ast.IndexExpression) Type {
+	left := in.expression(exp.Left)
+	index := in.expression(exp.Index)
+
+	switch l := prune(left).(type) {
+	case *Array:
+		in.expect(exp.Token, index, Int, "array index")
+		return l.Element
+	case *Hash:
+		in.expect(exp.Token, index, l.Key, "hash key")
+		return l.Value
+	case *Tuple:
+		if lit, ok := exp.Index.(*ast.IntegerLiteral); ok && lit.Value >= 0 && lit.Value < int64(len(l.Elements)) { // 1. 常量下标可以确定元组元素的类型
+			return l.Elements[lit.Value]
+		}
+		in.expect(exp.Token, index, Int, "tuple index")
+		return in.fresh()
+	case *Var:
+		elem := in.fresh()
+		if prune(index) == Int {
+			in.expect(exp.Token, l, &Array{Element: elem}, "index expression")
+		} else if _, unknown := prune(index).(*Var); !unknown {
+			in.expect(exp.Token, l, &Hash{Key: index, Value: elem}, "index expression")
+		}
+		return elem
+	case *Named:
+		return in.fresh() // 2. 枚举值可以通过 __index__ 支持下标
+	}
+
+	switch prune(left) {
+	case String:
+		in.expect(exp.Token, index, Int, "string index")
+		return Char
+	case Bytes, Range:
+		in.expect(exp.Token, index, Int, "index")
+		return Int
+	case Any:
+		return in.fresh()
+	}
+	names := describe(left, index)
+	in.errorf(exp.Token, "index operator not supported: %s[%s]", names[0], names[1])
+	return in.fresh()
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"punyGo/pkg/lexer"
+	"punyGo/pkg/parser"
+)
+
+// inferSource 解析一段程序并推断其类型，返回签名和发现的错误
+func inferSource(t *testing.T, input string) ([]Signature, []string) {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return Infer(program)
+}
+
+func TestInferConflictingInstantiations(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`fn same<T>(a: T, b: T) -> T { a } same(1, "a");`, "conflicting instantiations of T in call to same: int and string"},
+		{`fn firsts<T>(xs: [T], ys: [T]) -> T { xs[0] } firsts([1], ["a"]);`, "conflicting instantiations of T in call to firsts: int and string"},
+	}
+	for _, tt := range tests {
+		_, errs := inferSource(t, tt.input)
+		if len(errs) != 1 || !strings.Contains(errs[0], tt.want) {
+			t.Errorf("%s: got errors %v, want %q", tt.input, errs, tt.want)
+		}
+	}
+}
+
+func TestInferGenericSignature(t *testing.T) {
+	sigs, errs := inferSource(t, `fn same<T>(a: T, b: T) -> T { a } let x = same(1, 2);`)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	got := []string{}
+	for _, s := range sigs {
+		got = append(got, s.String())
+	}
+	if want := "same: fn(a, a) -> a; x: int"; strings.Join(got, "; ") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, "; "), want)
+	}
+}
+
+// expectSignatures 推断程序，要求没有错误且签名以 "; " 连接后等于 want
+func expectSignatures(t *testing.T, input, want string) {
+	t.Helper()
+	sigs, errs := inferSource(t, input)
+	if len(errs) != 0 {
+		t.Fatalf("%s: unexpected errors: %v", input, errs)
+	}
+	got := []string{}
+	for _, s := range sigs {
+		got = append(got, s.String())
+	}
+	if strings.Join(got, "; ") != want {
+		t.Errorf("%s: got %s, want %s", input, strings.Join(got, "; "), want)
+	}
+}
+
+func TestInferRecursiveLet(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let fact = fn(n) { if (n == 0) { 1 } else { n * fact(n - 1) } };`, "fact: fn(int) -> int"},
+		{`let f = "s"; let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } };`, "f: fn(int) -> int"},
+	}
+	for _, tt := range tests {
+		expectSignatures(t, tt.input, tt.want)
+	}
+}
+
+func TestInferTasksAndGenerators(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let id = fn(x) { x }; let t = spawn id(1);`, "id: fn(a) -> a; t: task"},
+		{`let count = fn*(n) { yield n; }; let g = count(1);`, "count: fn(a) -> generator; g: generator"},
+		{`let count = fn*(n) { yield n; }; let xs = [x for x in count(1)];`, "count: fn(a) -> generator; xs: [a]"},
+	}
+	for _, tt := range tests {
+		expectSignatures(t, tt.input, tt.want)
+	}
+
+	_, errs := inferSource(t, `let id = fn(x) { x }; let t = spawn id(1); t + 1;`)
+	if len(errs) == 0 || !strings.Contains(errs[0], "task + int") {
+		t.Errorf("got errors %v, want task + int", errs)
+	}
+}
+
+func TestInferHashMembers(t *testing.T) {
+	expectSignatures(t, `let h = {"a": 1}; let n = h.a; let m = h?.b;`, "h: {string: int}; n: int; m: int")
+	_, errs := inferSource(t, `let h = {1: 2}; h.a;`)
+	if len(errs) != 1 || !strings.Contains(errs[0], "cannot use int as string in hash member a") {
+		t.Errorf("got errors %v", errs)
+	}
+}
+
+func TestInferHashItems(t *testing.T) {
+	expectSignatures(t, `let h = {"a": 1}; let inverted = {v: k for (k, v) in items(h)};`, "h: {string: int}; inverted: {int: string}")
+}
+
+func TestInferMatchExpression(t *testing.T) {
+	enum := "enum Shape { Circle(r: int), Rect(w: int, h: int), Empty }; enum Color { Red }; "
+	expectSignatures(t, enum+`let area = fn(s) { match s { case Circle(r) { r * r } case Rect(_, h) { h } default { 0 } } };`, "area: fn(Shape) -> int")
+	expectSignatures(t, `enum Option<T> { Some(value: T), None }; let unwrap = fn(o, d) { match o { case Some(v) { v } case None { d } } };`, "unwrap: fn(Option<a>, a) -> a")
+	expectSignatures(t, enum+`let show = fn(s) { match s { case Circle(r) { puts(r) } case Empty { "empty" } }; s };`, "show: fn(Shape) -> Shape")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{enum + `match Red { case Circle(r) { r } };`, "case Circle can never match Color"},
+		{enum + `let x = match Empty { case Circle(r) { r } case Empty { "none" } };`, "match branches have different types: int and string"},
+		{enum + `match Empty { case Rect(w) { w } };`, "wrong number of names in pattern Rect: want=2, got=1"},
+		{enum + `let f = fn(x) { x }; match Empty { case f { 1 } };`, "case f in match must be a variant"},
+	}
+	for _, tt := range tests {
+		_, errs := inferSource(t, tt.input)
+		if len(errs) == 0 || !strings.Contains(errs[0], tt.want) {
+			t.Errorf("%s: got errors %v, want %q", tt.input, errs, tt.want)
+		}
+	}
+}
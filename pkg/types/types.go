@@ -0,0 +1,322 @@
+// Package types 实现 punyGo 的静态类型检查。
+// 类型标注是可选的：没有标注、也无法从字面量得知的类型都是 any，any 与任何类型兼容，因此没有标注的代码总能通过检查
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type 接口表示一个静态类型
+type Type interface {
+	String() string
+}
+
+// Basic 表示内置的基本类型
+type Basic struct {
+	Name string // 类型名
+}
+
+// String 返回类型名
+func (b *Basic) String() string { return b.Name }
+
+// 基本类型在整个检查器中只有唯一的实例，便于直接比较指针
+var (
+	Any    = &Basic{Name: "any"}    // 任意类型，用于渐进类型
+	Int    = &Basic{Name: "int"}    // 整数
+	Bool   = &Basic{Name: "bool"}   // 布尔值
+	String = &Basic{Name: "string"} // 字符串
+	Nil    = &Basic{Name: "nil"}    // 空值
+	Bytes  = &Basic{Name: "bytes"}  // 字节串
+	Char   = &Basic{Name: "char"}   // 字符
+	Regex  = &Basic{Name: "regex"}  // 正则表达式
+	Range  = &Basic{Name: "range"}  // 区间
+
+	Task      = &Basic{Name: "task"}      // spawn 返回的任务，只能交给 wait
+	Generator = &Basic{Name: "generator"} // 调用生成器函数得到的生成器，元素类型未知
+)
+
+// basicTypes 记录可以在类型标注中使用的基本类型名
+var basicTypes = map[string]Type{
+	"any":    Any,
+	"int":    Int,
+	"bool":   Bool,
+	"string": String,
+	"nil":    Nil,
+	"bytes":  Bytes,
+	"char":   Char,
+	"regex":  Regex,
+	"range":  Range,
+
+	"task":      Task,
+	"generator": Generator,
+}
+
+// Array 表示数组类型，例如 [int]
+type Array struct {
+	Element Type // 元素类型
+}
+
+// String 返回数组类型的字符串表示
+func (a *Array) String() string { return "[" + a.Element.String() + "]" }
+
+// Hash 表示哈希类型，例如 {string: int}
+type Hash struct {
+	Key   Type // 键的类型
+	Value Type // 值的类型
+}
+
+// String 返回哈希类型的字符串表示
+func (h *Hash) String() string { return "{" + h.Key.String() + ": " + h.Value.String() + "}" }
+
+// Set 表示集合类型，例如 #{int}
+type Set struct {
+	Element Type // 元素类型
+}
+
+// String 返回集合类型的字符串表示
+func (s *Set) String() string { return "#{" + s.Element.String() + "}" }
+
+// Tuple 表示元组类型，例如 (int, string)
+type Tuple struct {
+	Elements []Type // 各元素的类型
+}
+
+// String 返回元组类型的字符串表示
+func (t *Tuple) String() string {
+	elements := []string{}
+	for _, e := range t.Elements {
+		elements = append(elements, e.String())
+	}
+	if len(elements) == 1 {
+		return "(" + elements[0] + ",)"
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// Function 表示函数类型，例如 fn(int, int) -> int。泛型函数的 TypeParams 不为空，每次调用时重新确定类型参数
+type Function struct {
+	TypeParams []*TypeParam // 类型参数，不是泛型函数时为 nil
+	Params     []Type       // 各形参的类型
+	Names      []string     // 各形参的名字，用于检查关键字实参；函数类型标注中为 nil
+	Required   int          // 没有默认值的形参个数
+	Rest       Type         // 可变参数收集实参的数组的类型，没有可变参数时为 nil
+	Return     Type         // 返回值的类型
+}
+
+// String 返回函数类型的字符串表示
+func (f *Function) String() string {
+	params := []string{}
+	for _, p := range f.Params {
+		params = append(params, p.String())
+	}
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
+	}
+	generics := ""
+	if len(f.TypeParams) > 0 {
+		names := []string{}
+		for _, p := range f.TypeParams {
+			names = append(names, p.Name)
+		}
+		generics = "<" + strings.Join(names, ", ") + ">"
+	}
+	return "fn" + generics + "(" + strings.Join(params, ", ") + ") -> " + f.Return.String()
+}
+
+// Named 表示由 enum 声明的用户类型，泛型枚举的 Args 记录类型实参，例如 Pair<int, string>；
+// Args 为空表示没有给出类型实参，与同名的任何实例兼容
+type Named struct {
+	Name string // 枚举名称
+	Args []Type // 类型实参
+}
+
+// String 返回枚举名称和类型实参
+func (n *Named) String() string {
+	if len(n.Args) == 0 {
+		return n.Name
+	}
+	args := []string{}
+	for _, a := range n.Args {
+		args = append(args, a.String())
+	}
+	return n.Name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// Var 表示类型推断中的类型变量。Instance 不为 nil 时类型变量已经与该类型合一，
+// Kinds 不为 nil 时类型变量只能合一为这些种类的类型，用于记录操作符对操作数的要求
+type Var struct {
+	ID       int      // 编号，用于区分不同的类型变量
+	Level    int      // 创建时所在的 let 嵌套层次，用于判断能否泛化
+	Instance Type     // 合一得到的类型，未确定时为 nil
+	Kinds    []string // 允许的类型种类，nil 表示没有限制
+	Op       string   // 产生种类限制的操作符，用于错误信息
+	Param    string   // 由类型参数产生时为类型参数名，用于报告实例化冲突
+}
+
+// String 返回已确定的类型，未确定时返回类型变量的编号
+func (v *Var) String() string {
+	if v.Instance != nil {
+		return v.Instance.String()
+	}
+	return fmt.Sprintf("t%d", v.ID)
+}
+
+// TypeParam 表示有名字的类型参数，例如泛型函数声明的 T 或推断结果中的 a、b。
+// 不同的类型参数即使同名也是不同的类型，比较时使用指针
+type TypeParam struct {
+	Name string // 类型参数名
+}
+
+// String 返回类型参数名
+func (p *TypeParam) String() string { return p.Name }
+
+// assignable 判断类型为 from 的值能否用在需要 to 的地方。any 与任何类型兼容，
+// 复合类型逐个比较其组成部分，函数的形参类型按相反的方向比较
+func assignable(from, to Type) bool {
+	if from == Any || to == Any || from == to {
+		return true
+	}
+
+	switch to := to.(type) {
+	case *Array:
+		f, ok := from.(*Array)
+		return ok && assignable(f.Element, to.Element)
+	case *Hash:
+		f, ok := from.(*Hash)
+		return ok && assignable(f.Key, to.Key) && assignable(f.Value, to.Value)
+	case *Set:
+		f, ok := from.(*Set)
+		return ok && assignable(f.Element, to.Element)
+	case *Tuple:
+		f, ok := from.(*Tuple)
+		if !ok || len(f.Elements) != len(to.Elements) {
+			return false
+		}
+		for i := range to.Elements {
+			if !assignable(f.Elements[i], to.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *Function:
+		f, ok := from.(*Function)
+		if !ok || len(f.Params) != len(to.Params) {
+			return false
+		}
+		if len(f.TypeParams) > 0 { // 1. 泛型函数的类型参数可以是任何类型
+			f = eraseTypeParams(f)
+		}
+		for i := range to.Params {
+			if !assignable(to.Params[i], f.Params[i]) {
+				return false
+			}
+		}
+		return assignable(f.Return, to.Return)
+	case *Named:
+		f, ok := from.(*Named)
+		if !ok || f.Name != to.Name {
+			return false
+		}
+		if len(f.Args) != len(to.Args) { // 2. 没有类型实参的一方与任何实例兼容
+			return len(f.Args) == 0 || len(to.Args) == 0
+		}
+		for i := range to.Args {
+			if !assignable(f.Args[i], to.Args[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// join 返回两个分支的公共类型，不同时为 any
+func join(a, b Type) Type {
+	if assignable(a, b) && assignable(b, a) && a != Any && b != Any {
+		return a
+	}
+	if a == b {
+		return a
+	}
+	return Any
+}
+
+// kind 返回类型的种类，用于判断两个值能否比较；any 的种类为空
+func kind(t Type) string {
+	switch t := t.(type) {
+	case *Basic:
+		if t == Any {
+			return ""
+		}
+		return t.Name
+	case *Array:
+		return "array"
+	case *Hash:
+		return "hash"
+	case *Set:
+		return "set"
+	case *Tuple:
+		return "tuple"
+	case *Function:
+		return "function"
+	case *Named:
+		return "enum" // 运行时所有枚举值的类型相同，可以互相比较
+	}
+	return ""
+}
+
+// eraseTypeParams 把泛型函数的类型参数替换为 any，得到普通的函数类型
+func eraseTypeParams(f *Function) *Function {
+	own := map[*TypeParam]bool{}
+	for _, p := range f.TypeParams {
+		own[p] = true
+	}
+	return replaceTypeParams(f, func(p *TypeParam) Type {
+		if own[p] {
+			return Any
+		}
+		return p
+	}).(*Function)
+}
+
+// replaceTypeParams 复制类型，复制时用 fn 替换其中的类型参数；结果中的函数不再是泛型函数
+func replaceTypeParams(t Type, fn func(*TypeParam) Type) Type {
+	switch t := t.(type) {
+	case *TypeParam:
+		return fn(t)
+	case *Array:
+		return &Array{Element: replaceTypeParams(t.Element, fn)}
+	case *Set:
+		return &Set{Element: replaceTypeParams(t.Element, fn)}
+	case *Hash:
+		return &Hash{Key: replaceTypeParams(t.Key, fn), Value: replaceTypeParams(t.Value, fn)}
+	case *Tuple:
+		elements := make([]Type, len(t.Elements))
+		for i, e := range t.Elements {
+			elements[i] = replaceTypeParams(e, fn)
+		}
+		return &Tuple{Elements: elements}
+	case *Function:
+		f := &Function{Names: t.Names, Required: t.Required}
+		for _, p := range t.Params {
+			f.Params = append(f.Params, replaceTypeParams(p, fn))
+		}
+		if t.Rest != nil {
+			f.Rest = replaceTypeParams(t.Rest, fn)
+		}
+		f.Return = replaceTypeParams(t.Return, fn)
+		return f
+	case *Named:
+		if len(t.Args) == 0 {
+			return t
+		}
+		n := &Named{Name: t.Name}
+		for _, a := range t.Args {
+			n.Args = append(n.Args, replaceTypeParams(a, fn))
+		}
+		return n
+	}
+	return t
+}
@@ -0,0 +1,324 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prune 沿着已合一的类型变量找到其代表的类型
+func prune(t Type) Type {
+	for {
+		v, ok := t.(*Var)
+		if !ok || v.Instance == nil {
+			return t
+		}
+		t = v.Instance
+	}
+}
+
+// Scheme 表示类型模式，Vars 中的类型变量在每次使用时替换为新的类型变量，实现 let 多态
+type Scheme struct {
+	Vars []*Var // 被泛化的类型变量
+	Type Type   // 类型
+}
+
+// unifyError 表示合一失败，message 不为空时是比类型不匹配更具体的原因
+type unifyError struct {
+	message string
+}
+
+func (e *unifyError) Error() string { return e.message }
+
+// unify 合一两个类型，失败时返回错误。已经完成的部分合一不会撤销
+func unify(a, b Type) error {
+	a, b = prune(a), prune(b)
+	if a == b {
+		return nil
+	}
+
+	if va, ok := a.(*Var); ok { // 1. 类型变量与任何满足其限制的类型合一
+		return bindVar(va, b)
+	}
+	if vb, ok := b.(*Var); ok {
+		return bindVar(vb, a)
+	}
+	if a == Any || b == Any { // 2. 类型标注中的 any 与任何类型合一
+		return nil
+	}
+
+	mismatch := &unifyError{}
+	switch a := a.(type) {
+	case *Array:
+		if b, ok := b.(*Array); ok {
+			return unify(a.Element, b.Element)
+		}
+	case *Set:
+		if b, ok := b.(*Set); ok {
+			return unify(a.Element, b.Element)
+		}
+	case *Hash:
+		if b, ok := b.(*Hash); ok {
+			if err := unify(a.Key, b.Key); err != nil {
+				return err
+			}
+			return unify(a.Value, b.Value)
+		}
+	case *Tuple:
+		if b, ok := b.(*Tuple); ok && len(a.Elements) == len(b.Elements) {
+			for i := range a.Elements {
+				if err := unify(a.Elements[i], b.Elements[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case *Function:
+		if b, ok := b.(*Function); ok {
+			return unifyFunctions(a, b)
+		}
+	case *Named:
+		if b, ok := b.(*Named); ok && a.Name == b.Name {
+			if len(a.Args) != len(b.Args) { // 没有类型实参的一方与任何实例合一
+				return nil
+			}
+			for i := range a.Args {
+				if err := unify(a.Args[i], b.Args[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case *TypeParam:
+		if b, ok := b.(*TypeParam); ok && a.Name == b.Name {
+			return nil
+		}
+	}
+	return mismatch
+}
+
+// unifyFunctions 合一两个函数类型。形参个数不同时，较短的一方必须能提供较长一方所有必需的形参
+func unifyFunctions(a, b *Function) error {
+	short, long := a, b
+	if len(a.Params) > len(b.Params) {
+		short, long = b, a
+	}
+	if len(short.Params) < long.Required {
+		return &unifyError{}
+	}
+	for i := range short.Params {
+		if err := unify(a.Params[i], b.Params[i]); err != nil {
+			return err
+		}
+	}
+	if a.Rest != nil && b.Rest != nil {
+		if err := unify(a.Rest, b.Rest); err != nil {
+			return err
+		}
+	}
+	return unify(a.Return, b.Return)
+}
+
+// bindVar 把类型变量 v 合一为类型 t，检查 t 中是否出现 v 以及 t 是否满足 v 的种类限制
+func bindVar(v *Var, t Type) error {
+	if tv, ok := t.(*Var); ok { // 1. 两个类型变量合一时合并种类限制
+		kinds := intersectKinds(v.Kinds, tv.Kinds)
+		if kinds != nil && len(kinds) == 0 {
+			return &unifyError{fmt.Sprintf("no type supports both %s and %s", v.Op, tv.Op)}
+		}
+		if tv.Kinds == nil {
+			tv.Op = v.Op
+		}
+		tv.Kinds = kinds
+		if v.Level < tv.Level {
+			tv.Level = v.Level
+		}
+		v.Instance = tv
+		return nil
+	}
+
+	if occurs(v, t) { // 2. 不允许无限类型，例如 a = [a]
+		names := describe(v, t)
+		return &unifyError{fmt.Sprintf("infinite type: %s occurs in %s", names[0], names[1])}
+	}
+	adjustLevels(t, v.Level)
+	v.Instance = t
+	if !allowsKind(v, t) { // 3. 检查操作符对类型种类的要求，不满足时仍然完成合一，避免同一个错误重复报告
+		return &unifyError{fmt.Sprintf("%s does not support %s", describe(t)[0], v.Op)}
+	}
+	return nil
+}
+
+// allowsKind 判断类型 t 是否满足类型变量 v 的种类限制，枚举值可以通过特殊方法重载操作符，总是满足
+func allowsKind(v *Var, t Type) bool {
+	if v.Kinds == nil || t == Any {
+		return true
+	}
+	if _, ok := t.(*Named); ok {
+		return true
+	}
+	for _, k := range v.Kinds {
+		if kind(t) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectKinds 求两个种类限制的交集，nil 表示没有限制
+func intersectKinds(a, b []string) []string {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	kinds := []string{}
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				kinds = append(kinds, x)
+			}
+		}
+	}
+	return kinds
+}
+
+// occurs 判断类型变量 v 是否出现在类型 t 中
+func occurs(v *Var, t Type) bool {
+	found := false
+	walk(t, func(w *Var) {
+		if w == v {
+			found = true
+		}
+	})
+	return found
+}
+
+// adjustLevels 把 t 中类型变量的层次降低到 level，避免它们在外层被错误地泛化
+func adjustLevels(t Type, level int) {
+	walk(t, func(w *Var) {
+		if w.Level > level {
+			w.Level = level
+		}
+	})
+}
+
+// walk 依次访问类型中所有未确定的类型变量
+func walk(t Type, fn func(*Var)) {
+	switch t := prune(t).(type) {
+	case *Var:
+		fn(t)
+	case *Array:
+		walk(t.Element, fn)
+	case *Set:
+		walk(t.Element, fn)
+	case *Hash:
+		walk(t.Key, fn)
+		walk(t.Value, fn)
+	case *Tuple:
+		for _, e := range t.Elements {
+			walk(e, fn)
+		}
+	case *Function:
+		for _, p := range t.Params {
+			walk(p, fn)
+		}
+		if t.Rest != nil {
+			walk(t.Rest, fn)
+		}
+		walk(t.Return, fn)
+	case *Named:
+		for _, a := range t.Args {
+			walk(a, fn)
+		}
+	}
+}
+
+// substitute 复制类型，复制时用 fn 替换其中未确定的类型变量
+func substitute(t Type, fn func(*Var) Type) Type {
+	switch t := prune(t).(type) {
+	case *Var:
+		return fn(t)
+	case *Array:
+		return &Array{Element: substitute(t.Element, fn)}
+	case *Set:
+		return &Set{Element: substitute(t.Element, fn)}
+	case *Hash:
+		return &Hash{Key: substitute(t.Key, fn), Value: substitute(t.Value, fn)}
+	case *Tuple:
+		elements := make([]Type, len(t.Elements))
+		for i, e := range t.Elements {
+			elements[i] = substitute(e, fn)
+		}
+		return &Tuple{Elements: elements}
+	case *Function:
+		f := &Function{Names: t.Names, Required: t.Required}
+		for _, p := range t.Params {
+			f.Params = append(f.Params, substitute(p, fn))
+		}
+		if t.Rest != nil {
+			f.Rest = substitute(t.Rest, fn)
+		}
+		f.Return = substitute(t.Return, fn)
+		return f
+	case *Named:
+		if len(t.Args) == 0 {
+			return t
+		}
+		n := &Named{Name: t.Name}
+		for _, a := range t.Args {
+			n.Args = append(n.Args, substitute(a, fn))
+		}
+		return n
+	default:
+		return t
+	}
+}
+
+// namer 按出现顺序为类型变量分配 a、b、c 等名字，用于输出推断结果和错误信息
+type namer struct {
+	names map[*Var]*TypeParam // 已分配的名字
+	order []*Var              // 分配名字的顺序
+}
+
+// newNamer 创建一个 namer
+func newNamer() *namer {
+	return &namer{names: map[*Var]*TypeParam{}}
+}
+
+// apply 返回把类型变量替换为其名字后的类型
+func (n *namer) apply(t Type) Type {
+	return substitute(t, func(v *Var) Type {
+		if p, ok := n.names[v]; ok {
+			return p
+		}
+		name := string(rune('a' + len(n.order)%26))
+		if len(n.order) >= 26 {
+			name += fmt.Sprint(len(n.order) / 26)
+		}
+		n.names[v] = &TypeParam{Name: name}
+		n.order = append(n.order, v)
+		return n.names[v]
+	})
+}
+
+// constraints 返回已命名的类型变量上的种类限制，例如 "a: int | string | bytes"
+func (n *namer) constraints() []string {
+	result := []string{}
+	for _, v := range n.order {
+		if v.Kinds != nil {
+			result = append(result, n.names[v].Name+": "+strings.Join(v.Kinds, " | "))
+		}
+	}
+	return result
+}
+
+// describe 使用同一组名字返回多个类型的字符串表示
+func describe(types ...Type) []string {
+	n := newNamer()
+	result := make([]string, len(types))
+	for i, t := range types {
+		result[i] = n.apply(t).String()
+	}
+	return result
+}